@@ -0,0 +1,126 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package achievements
+
+import (
+	"Unbewohnte/capyclick/save"
+	"time"
+)
+
+type ConditionKind uint8
+
+const (
+	ClicksReached ConditionKind = iota
+	LevelReached
+	MandarinRainsCompleted
+	PlaytimeReached
+)
+
+// Declarative requirement: some save statistic has to reach the target
+type Condition struct {
+	Kind   ConditionKind
+	Target uint64
+}
+
+type Achievement struct {
+	ID          string
+	Name        string
+	Description string
+	Condition   Condition
+}
+
+// All achievements in the order they are shown
+var List = []Achievement{
+	{ID: "first_click", Name: "Hello there", Description: "Click the capybara", Condition: Condition{ClicksReached, 1}},
+	{ID: "clicks_1000", Name: "Dedicated petter", Description: "Click 1000 times", Condition: Condition{ClicksReached, 1000}},
+	{ID: "clicks_10000", Name: "Capybara whisperer", Description: "Click 10000 times", Condition: Condition{ClicksReached, 10000}},
+	{ID: "level_3", Name: "All grown up", Description: "Reach level 3", Condition: Condition{LevelReached, 3}},
+	{ID: "level_10", Name: "Seasoned capybara", Description: "Reach level 10", Condition: Condition{LevelReached, 10}},
+	{ID: "level_25", Name: "Capybara elder", Description: "Reach level 25", Condition: Condition{LevelReached, 25}},
+	{ID: "rain_1", Name: "Citrus catcher", Description: "Complete a mandarin rain", Condition: Condition{MandarinRainsCompleted, 1}},
+	{ID: "rain_25", Name: "Mandarin magnate", Description: "Complete 25 mandarin rains", Condition: Condition{MandarinRainsCompleted, 25}},
+	{ID: "playtime_hour", Name: "Relaxing hour", Description: "Play for an hour", Condition: Condition{PlaytimeReached, 60 * 60}},
+	{ID: "playtime_day", Name: "Hot spring regular", Description: "Play for 24 hours", Condition: Condition{PlaytimeReached, 24 * 60 * 60}},
+}
+
+// Returns the current value of the statistic the condition watches
+func (c Condition) current(s save.Save) uint64 {
+	switch c.Kind {
+	case ClicksReached:
+		return s.TimesClicked
+	case LevelReached:
+		return uint64(s.Level)
+	case MandarinRainsCompleted:
+		return s.MandarinRainsCompleted
+	case PlaytimeReached:
+		return s.PlaytimeSeconds
+	default:
+		return 0
+	}
+}
+
+// Returns how close the save is to meeting the condition in [0.0; 1.0]
+func (c Condition) Progress(s save.Save) float64 {
+	if c.Target == 0 {
+		return 1.0
+	}
+
+	current := c.current(s)
+	if current >= c.Target {
+		return 1.0
+	}
+
+	return float64(current) / float64(c.Target)
+}
+
+func (c Condition) Met(s save.Save) bool {
+	return c.current(s) >= c.Target
+}
+
+// Returns true and unlock time if the achievement is recorded in the save
+func (a Achievement) Unlocked(s save.Save) (bool, time.Time) {
+	unlockedUnix, ok := s.Achievements[a.ID]
+	if !ok {
+		return false, time.Time{}
+	}
+
+	return true, time.Unix(int64(unlockedUnix), 0)
+}
+
+// Records all newly met achievements in the save and returns them
+func Check(s *save.Save, now time.Time) []Achievement {
+	var unlocked []Achievement
+	for _, achievement := range List {
+		if _, ok := s.Achievements[achievement.ID]; ok {
+			continue
+		}
+
+		if !achievement.Condition.Met(*s) {
+			continue
+		}
+
+		if s.Achievements == nil {
+			s.Achievements = make(map[string]uint64)
+		}
+		s.Achievements[achievement.ID] = uint64(now.Unix())
+		unlocked = append(unlocked, achievement)
+	}
+
+	return unlocked
+}
@@ -0,0 +1,274 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package animation
+
+import (
+	"errors"
+	"image"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func loadTestClips(t *testing.T) Library {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "clips.json"))
+	if err != nil {
+		t.Fatalf("failed to read clips: %s", err)
+	}
+
+	library, err := Parse(data)
+	if err != nil {
+		t.Fatalf("failed to parse clips: %s", err)
+	}
+
+	return library
+}
+
+func TestParse(t *testing.T) {
+	library := loadTestClips(t)
+	if len(library) != 4 {
+		t.Fatalf("expected 4 clips, got %d", len(library))
+	}
+
+	walk := library["walk"]
+	if walk.Mode != Loop || len(walk.Frames) != 3 {
+		t.Fatalf("walk clip was not parsed properly: %+v", walk)
+	}
+	if walk.Frames[1].Rect() != image.Rect(8, 0, 16, 8) {
+		t.Errorf("unexpected frame rectangle %v", walk.Frames[1].Rect())
+	}
+	if walk.Duration() != 300*time.Millisecond {
+		t.Errorf("expected walk to last 300ms, got %s", walk.Duration())
+	}
+
+	// Defaults
+	if library["still"].Mode != Loop {
+		t.Errorf("clips loop by default")
+	}
+	wave := library["wave"].Frames[0]
+	if wave.ScaleX != 1.0 || wave.ScaleY != 1.0 {
+		t.Errorf("scale defaults to 1, got %f and %f", wave.ScaleX, wave.ScaleY)
+	}
+	if wave.Rect() != (image.Rectangle{}) {
+		t.Errorf("frames without size take the whole image, got %v", wave.Rect())
+	}
+
+	jump := library["jump"].Frames
+	if jump[0].ScaleY != 0.8 || jump[0].ScaleX != 1.0 || jump[1].OffsetY != -4 || jump[1].Rotation != 15 {
+		t.Errorf("transformations were not parsed: %+v", jump)
+	}
+}
+
+func TestParseRejectsBrokenClips(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"no name", `{"clips": [{"frames": [{"image": "a.png", "durationMs": 1}]}]}`},
+		{"twice", `{"clips": [{"name": "a", "frames": [{"image": "a.png", "durationMs": 1}]}, {"name": "a", "frames": [{"image": "a.png", "durationMs": 1}]}]}`},
+		{"unknown mode", `{"clips": [{"name": "a", "mode": "bounce", "frames": [{"image": "a.png", "durationMs": 1}]}]}`},
+		{"no frames", `{"clips": [{"name": "a", "frames": []}]}`},
+		{"no image", `{"clips": [{"name": "a", "frames": [{"durationMs": 1}]}]}`},
+		{"no duration", `{"clips": [{"name": "a", "frames": [{"image": "a.png"}]}]}`},
+		{"negative size", `{"clips": [{"name": "a", "frames": [{"image": "a.png", "width": -1, "durationMs": 1}]}]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse([]byte(test.data))
+			if !errors.Is(err, ErrInvalidClip) {
+				t.Errorf("expected ErrInvalidClip, got %v", err)
+			}
+		})
+	}
+
+	_, err := Parse([]byte("not json"))
+	if err == nil {
+		t.Errorf("garbage was parsed")
+	}
+}
+
+// Returns frame indices and events seen while stepping the player
+func run(player *Player, step time.Duration, steps int) ([]int, []string) {
+	var frames []int
+	var events []string
+	for i := 0; i < steps; i++ {
+		events = append(events, player.Update(step)...)
+		frames = append(frames, player.FrameIndex())
+	}
+	return frames, events
+}
+
+func TestLoop(t *testing.T) {
+	library := loadTestClips(t)
+	var player Player
+	player.Play(library["walk"])
+
+	frames, events := run(&player, 100*time.Millisecond, 7)
+	expectedFrames := []int{1, 2, 0, 1, 2, 0, 1}
+	if !reflect.DeepEqual(frames, expectedFrames) {
+		t.Errorf("expected frames %v, got %v", expectedFrames, frames)
+	}
+
+	// The first frame reports its event as soon as the clip starts
+	expectedEvents := []string{"step", "step", "step", "step", "step"}
+	if !reflect.DeepEqual(events, expectedEvents) {
+		t.Errorf("expected events %v, got %v", expectedEvents, events)
+	}
+	if player.Finished() {
+		t.Errorf("looping clip has finished")
+	}
+}
+
+func TestPingPong(t *testing.T) {
+	library := loadTestClips(t)
+	var player Player
+	player.Play(library["wave"])
+
+	frames, events := run(&player, 50*time.Millisecond, 6)
+	expectedFrames := []int{1, 2, 1, 0, 1, 2}
+	if !reflect.DeepEqual(frames, expectedFrames) {
+		t.Errorf("expected frames %v, got %v", expectedFrames, frames)
+	}
+
+	expectedEvents := []string{"middle", "edge", "middle", "middle", "edge"}
+	if !reflect.DeepEqual(events, expectedEvents) {
+		t.Errorf("expected events %v, got %v", expectedEvents, events)
+	}
+}
+
+func TestOnce(t *testing.T) {
+	library := loadTestClips(t)
+	var player Player
+	player.Play(library["jump"])
+
+	events := player.Update(150 * time.Millisecond)
+	if !reflect.DeepEqual(events, []string{"takeoff"}) {
+		t.Errorf("expected takeoff event, got %v", events)
+	}
+
+	player.Update(100 * time.Millisecond)
+	if player.FrameIndex() != 1 || player.Finished() {
+		t.Fatalf("expected to be on the last frame, got %d", player.FrameIndex())
+	}
+
+	player.Update(250 * time.Millisecond)
+	if !player.Finished() {
+		t.Errorf("clip did not finish after its whole duration")
+	}
+	if player.FrameIndex() != 1 {
+		t.Errorf("finished clip must stay on its last frame, got %d", player.FrameIndex())
+	}
+	if events := player.Update(time.Hour); len(events) != 0 {
+		t.Errorf("finished clip reported events %v", events)
+	}
+
+	// Playing again starts over
+	player.Play(library["jump"])
+	if player.Finished() || player.FrameIndex() != 0 {
+		t.Errorf("clip did not start over")
+	}
+}
+
+func TestBigStepsDoNotSkipEvents(t *testing.T) {
+	library := loadTestClips(t)
+	var player Player
+	player.Play(library["walk"])
+
+	events := player.Update(time.Second)
+	// Frame 0 at start, then 10 frame changes: 1 2 0 1 2 0 1 2 0 1
+	if len(events) != 7 {
+		t.Errorf("expected 7 steps in a second, got %d: %v", len(events), events)
+	}
+	if player.FrameIndex() != 1 {
+		t.Errorf("expected to be on frame 1, got %d", player.FrameIndex())
+	}
+}
+
+func TestSingleFrameLoop(t *testing.T) {
+	library := loadTestClips(t)
+	var player Player
+	player.Play(library["still"])
+
+	_, events := run(&player, 500*time.Millisecond, 4)
+	if !reflect.DeepEqual(events, []string{"tick", "tick", "tick"}) {
+		t.Errorf("expected a tick every loop, got %v", events)
+	}
+}
+
+func TestStop(t *testing.T) {
+	library := loadTestClips(t)
+	var player Player
+
+	if _, ok := player.Frame(); ok {
+		t.Errorf("idle player has a frame")
+	}
+
+	player.Play(library["walk"])
+	if !player.Playing("walk") || player.Playing("wave") {
+		t.Errorf("player does not know what it plays")
+	}
+	frame, ok := player.Frame()
+	if !ok || frame.Image != "walk.png" {
+		t.Errorf("expected the first walk frame, got %+v", frame)
+	}
+
+	player.Stop()
+	if player.Clip() != nil || player.Update(time.Second) != nil {
+		t.Errorf("stopped player keeps playing")
+	}
+}
+
+// The definitions shipped with the game must load and point at existing images
+func TestGameAnimations(t *testing.T) {
+	dir := filepath.Join("..", "resources", "resources")
+	data, err := os.ReadFile(filepath.Join(dir, "animations.json"))
+	if err != nil {
+		t.Fatalf("failed to read game animations: %s", err)
+	}
+
+	library, err := Parse(data)
+	if err != nil {
+		t.Fatalf("failed to parse game animations: %s", err)
+	}
+
+	for name, clip := range library {
+		for index, frame := range clip.Frames {
+			file, err := os.Open(filepath.Join(dir, frame.Image))
+			if err != nil {
+				t.Fatalf("%s frame %d: %s", name, index, err)
+			}
+			img, _, err := image.DecodeConfig(file)
+			file.Close()
+			if err != nil {
+				t.Fatalf("%s frame %d: %s", name, index, err)
+			}
+
+			rect := frame.Rect()
+			if !rect.In(image.Rect(0, 0, img.Width, img.Height)) {
+				t.Errorf("%s frame %d is out of %s: %v", name, index, frame.Image, rect)
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package animation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"time"
+)
+
+type Mode string
+
+const (
+	// Plays frames once and stops at the last one
+	Once Mode = "once"
+	// Starts over after the last frame
+	Loop Mode = "loop"
+	// Goes back and forth between the first and the last frame
+	PingPong Mode = "pingpong"
+)
+
+var ErrInvalidClip error = errors.New("invalid animation clip")
+
+type Frame struct {
+	// Image file the frame is taken from
+	Image string `json:"image"`
+	// Part of the image with the frame, the whole image if width or height is zero
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// How long the frame stays on screen
+	DurationMs uint32 `json:"durationMs"`
+	// Reported when the frame is reached, nothing if empty
+	Event string `json:"event"`
+	// Transformations in image pixels and degrees, scale of zero means 1.0
+	OffsetX  float64 `json:"offsetX"`
+	OffsetY  float64 `json:"offsetY"`
+	ScaleX   float64 `json:"scaleX"`
+	ScaleY   float64 `json:"scaleY"`
+	Rotation float64 `json:"rotation"`
+}
+
+// Returns the part of the image the frame is taken from, empty for the whole image
+func (f Frame) Rect() image.Rectangle {
+	if f.Width == 0 || f.Height == 0 {
+		return image.Rectangle{}
+	}
+
+	return image.Rect(f.X, f.Y, f.X+f.Width, f.Y+f.Height)
+}
+
+func (f Frame) Duration() time.Duration {
+	return time.Duration(f.DurationMs) * time.Millisecond
+}
+
+// Named sequence of frames
+type Clip struct {
+	Name   string  `json:"name"`
+	Mode   Mode    `json:"mode"`
+	Frames []Frame `json:"frames"`
+}
+
+// Returns how long a single pass through all frames takes
+func (c *Clip) Duration() time.Duration {
+	var total time.Duration
+	for _, frame := range c.Frames {
+		total += frame.Duration()
+	}
+
+	return total
+}
+
+// Clips by their names
+type Library map[string]*Clip
+
+// Parses clip definitions, filling in defaults and rejecting clips that can not be played
+func Parse(data []byte) (Library, error) {
+	var definitions struct {
+		Clips []*Clip `json:"clips"`
+	}
+	err := json.Unmarshal(data, &definitions)
+	if err != nil {
+		return nil, err
+	}
+
+	library := make(Library, len(definitions.Clips))
+	for _, clip := range definitions.Clips {
+		if clip.Name == "" {
+			return nil, fmt.Errorf("%w: clip without a name", ErrInvalidClip)
+		}
+		if _, exists := library[clip.Name]; exists {
+			return nil, fmt.Errorf("%w: %s is defined twice", ErrInvalidClip, clip.Name)
+		}
+
+		switch clip.Mode {
+		case "":
+			clip.Mode = Loop
+		case Once, Loop, PingPong:
+		default:
+			return nil, fmt.Errorf("%w: %s has unknown mode \"%s\"", ErrInvalidClip, clip.Name, clip.Mode)
+		}
+
+		if len(clip.Frames) == 0 {
+			return nil, fmt.Errorf("%w: %s has no frames", ErrInvalidClip, clip.Name)
+		}
+
+		for index := range clip.Frames {
+			frame := &clip.Frames[index]
+			if frame.Image == "" {
+				return nil, fmt.Errorf("%w: frame %d of %s has no image", ErrInvalidClip, index, clip.Name)
+			}
+			if frame.DurationMs == 0 {
+				return nil, fmt.Errorf("%w: frame %d of %s has no duration", ErrInvalidClip, index, clip.Name)
+			}
+			if frame.Width < 0 || frame.Height < 0 {
+				return nil, fmt.Errorf("%w: frame %d of %s has negative size", ErrInvalidClip, index, clip.Name)
+			}
+			if frame.ScaleX == 0.0 {
+				frame.ScaleX = 1.0
+			}
+			if frame.ScaleY == 0.0 {
+				frame.ScaleY = 1.0
+			}
+		}
+
+		library[clip.Name] = clip
+	}
+
+	return library, nil
+}
@@ -0,0 +1,145 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package animation
+
+import "time"
+
+// Plays a clip, keeping track of the current frame
+type Player struct {
+	clip      *Clip
+	frame     int
+	elapsed   time.Duration
+	direction int
+	finished  bool
+	// The first frame's event is yet to be reported
+	started bool
+}
+
+// Starts the clip over from its first frame
+func (p *Player) Play(clip *Clip) {
+	p.clip = clip
+	p.frame = 0
+	p.elapsed = 0
+	p.direction = 1
+	p.finished = false
+	p.started = false
+}
+
+// Stops playing anything
+func (p *Player) Stop() {
+	p.clip = nil
+}
+
+// Returns the clip being played or nil
+func (p *Player) Clip() *Clip {
+	return p.clip
+}
+
+// Returns true if a clip with given name is being played
+func (p *Player) Playing(name string) bool {
+	return p.clip != nil && p.clip.Name == name
+}
+
+// Returns true once a clip played once has shown its last frame for its whole duration
+func (p *Player) Finished() bool {
+	return p.finished
+}
+
+// Returns the frame to show and false if nothing is played
+func (p *Player) Frame() (Frame, bool) {
+	if p.clip == nil {
+		return Frame{}, false
+	}
+
+	return p.clip.Frames[p.frame], true
+}
+
+// Returns the index of the current frame
+func (p *Player) FrameIndex() int {
+	return p.frame
+}
+
+// Moves to the next frame according to the clip's mode. Returns false if there is none
+func (p *Player) advance() bool {
+	last := len(p.clip.Frames) - 1
+
+	switch p.clip.Mode {
+	case Once:
+		if p.frame == last {
+			p.finished = true
+			return false
+		}
+		p.frame++
+
+	case PingPong:
+		if last == 0 {
+			return false
+		}
+		if p.frame+p.direction < 0 || p.frame+p.direction > last {
+			p.direction = -p.direction
+		}
+		p.frame += p.direction
+
+	default:
+		p.frame = (p.frame + 1) % (last + 1)
+	}
+
+	return true
+}
+
+// Moves the clip on by delta and returns events of the frames reached, in order
+func (p *Player) Update(delta time.Duration) []string {
+	if p.clip == nil || p.finished {
+		return nil
+	}
+
+	var events []string
+	if !p.started {
+		p.started = true
+		if event := p.clip.Frames[p.frame].Event; event != "" {
+			events = append(events, event)
+		}
+	}
+
+	if delta > 0 {
+		p.elapsed += delta
+	}
+
+	for !p.finished {
+		duration := p.clip.Frames[p.frame].Duration()
+		if duration <= 0 || p.elapsed < duration {
+			break
+		}
+		p.elapsed -= duration
+
+		if !p.advance() {
+			continue
+		}
+
+		if event := p.clip.Frames[p.frame].Event; event != "" {
+			events = append(events, event)
+		}
+	}
+
+	if p.finished {
+		p.elapsed = 0
+	}
+
+	return events
+}
@@ -0,0 +1,119 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package atlas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"sort"
+)
+
+var ErrTooWide error = errors.New("image is wider than the atlas")
+
+// Many small images combined into a single one
+type Atlas struct {
+	Image   *image.RGBA
+	regions map[string]image.Rectangle
+}
+
+// Packs images into rows no wider than maxWidth, keeping padding
+// transparent pixels around each so that filtering does not bleed neighbours in
+func Pack(images map[string]image.Image, maxWidth int, padding int) (*Atlas, error) {
+	names := make([]string, 0, len(images))
+	for name := range images {
+		names = append(names, name)
+	}
+
+	// Tallest first packs rows tighter, names keep the layout the same between runs
+	sort.Slice(names, func(i, j int) bool {
+		hi := images[names[i]].Bounds().Dy()
+		hj := images[names[j]].Bounds().Dy()
+		if hi != hj {
+			return hi > hj
+		}
+		return names[i] < names[j]
+	})
+
+	regions := make(map[string]image.Rectangle, len(names))
+	x, y := padding, padding
+	rowHeight := 0
+	width := 0
+	for _, name := range names {
+		size := images[name].Bounds().Size()
+		if size.X+padding*2 > maxWidth {
+			return nil, fmt.Errorf("%w: %s is %d pixels wide", ErrTooWide, name, size.X)
+		}
+
+		if x+size.X+padding > maxWidth {
+			// Start a new row
+			x = padding
+			y += rowHeight + padding
+			rowHeight = 0
+		}
+
+		regions[name] = image.Rectangle{Min: image.Pt(x, y), Max: image.Pt(x, y).Add(size)}
+		x += size.X + padding
+		if x > width {
+			width = x
+		}
+		if size.Y > rowHeight {
+			rowHeight = size.Y
+		}
+	}
+
+	packed := image.NewRGBA(image.Rect(0, 0, width, y+rowHeight+padding))
+	for name, region := range regions {
+		img := images[name]
+		draw.Draw(packed, region, img, img.Bounds().Min, draw.Src)
+	}
+
+	return &Atlas{
+		Image:   packed,
+		regions: regions,
+	}, nil
+}
+
+// Returns where the image with given name is in the atlas
+func (a *Atlas) Region(name string) (image.Rectangle, bool) {
+	region, ok := a.regions[name]
+	return region, ok
+}
+
+// Returns names of all packed images, sorted
+func (a *Atlas) Names() []string {
+	names := make([]string, 0, len(a.regions))
+	for name := range a.regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Returns the part of the atlas with given image or nil if it is not packed
+func (a *Atlas) SubImage(name string) image.Image {
+	region, ok := a.regions[name]
+	if !ok {
+		return nil
+	}
+
+	return a.Image.SubImage(region)
+}
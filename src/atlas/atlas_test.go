@@ -0,0 +1,168 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package atlas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// Returns an image of given size filled with a single color
+func filled(width int, height int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func testImages() map[string]image.Image {
+	return map[string]image.Image{
+		"capybara.png": filled(32, 32, color.RGBA{120, 80, 40, 255}),
+		"orange.png":   filled(6, 5, color.RGBA{255, 165, 0, 255}),
+		"box.png":      filled(20, 11, color.RGBA{150, 100, 50, 255}),
+		"box_2.png":    filled(20, 9, color.RGBA{160, 110, 60, 255}),
+		"wide.png":     filled(50, 3, color.RGBA{0, 0, 255, 255}),
+	}
+}
+
+func TestPack(t *testing.T) {
+	images := testImages()
+	packed, err := Pack(images, 64, 1)
+	if err != nil {
+		t.Fatalf("failed to pack: %s", err)
+	}
+
+	if packed.Image.Bounds().Dx() > 64 {
+		t.Errorf("atlas is wider than allowed: %d", packed.Image.Bounds().Dx())
+	}
+
+	var regions []image.Rectangle
+	for name, img := range images {
+		region, ok := packed.Region(name)
+		if !ok {
+			t.Fatalf("%s is missing from the atlas", name)
+		}
+		if region.Size() != img.Bounds().Size() {
+			t.Errorf("%s: expected size %v, got %v", name, img.Bounds().Size(), region.Size())
+		}
+		if !region.In(packed.Image.Bounds()) {
+			t.Errorf("%s is out of the atlas: %v", name, region)
+		}
+
+		// Pixels are copied as they are
+		sub := packed.SubImage(name)
+		for x := 0; x < region.Dx(); x++ {
+			for y := 0; y < region.Dy(); y++ {
+				got := sub.At(region.Min.X+x, region.Min.Y+y)
+				want := img.At(x, y)
+				if got != color.RGBAModel.Convert(want) {
+					t.Fatalf("%s: pixel %d,%d is %v instead of %v", name, x, y, got, want)
+				}
+			}
+		}
+
+		regions = append(regions, region)
+	}
+
+	// Regions keep the padding between each other
+	for i := range regions {
+		for j := i + 1; j < len(regions); j++ {
+			if regions[i].Inset(-1).Overlaps(regions[j]) {
+				t.Errorf("regions %v and %v are too close", regions[i], regions[j])
+			}
+		}
+	}
+}
+
+func TestPackIsDeterministic(t *testing.T) {
+	first, err := Pack(testImages(), 64, 1)
+	if err != nil {
+		t.Fatalf("failed to pack: %s", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		again, err := Pack(testImages(), 64, 1)
+		if err != nil {
+			t.Fatalf("failed to pack: %s", err)
+		}
+
+		for _, name := range first.Names() {
+			a, _ := first.Region(name)
+			b, _ := again.Region(name)
+			if a != b {
+				t.Fatalf("%s moved from %v to %v", name, a, b)
+			}
+		}
+	}
+}
+
+func TestPackTooWide(t *testing.T) {
+	_, err := Pack(testImages(), 40, 1)
+	if !errors.Is(err, ErrTooWide) {
+		t.Errorf("expected ErrTooWide, got %v", err)
+	}
+}
+
+func TestPackNothing(t *testing.T) {
+	packed, err := Pack(nil, 64, 1)
+	if err != nil {
+		t.Fatalf("failed to pack nothing: %s", err)
+	}
+	if len(packed.Names()) != 0 {
+		t.Errorf("empty atlas has images")
+	}
+	if packed.SubImage("missing.png") != nil {
+		t.Errorf("got an image that was never packed")
+	}
+}
+
+func BenchmarkPack(b *testing.B) {
+	images := make(map[string]image.Image)
+	for i := 0; i < 64; i++ {
+		images[fmt.Sprintf("sprite_%d.png", i)] = filled(8+i%24, 8+i%16, color.White)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := Pack(images, 512, 1)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRegion(b *testing.B) {
+	packed, err := Pack(testImages(), 64, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		packed.Region("orange.png")
+	}
+}
@@ -0,0 +1,227 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package bignum
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Non-negative integer of arbitrary size. Zero value is a ready to use 0.
+// Numbers are immutable, every operation returns a new one
+type Number struct {
+	i *big.Int
+}
+
+var ErrInvalidNumber error = errors.New("invalid number")
+
+// Returns a number with given value
+func New(value uint64) Number {
+	return Number{i: new(big.Int).SetUint64(value)}
+}
+
+// Returns a number with integer part of given float. Negative and NaN values become 0
+func FromFloat(value float64) Number {
+	if math.IsNaN(value) || value <= 0.0 {
+		return Number{}
+	}
+
+	if math.IsInf(value, 1) {
+		value = math.MaxFloat64
+	}
+
+	i, _ := big.NewFloat(value).Int(nil)
+	return Number{i: i}
+}
+
+// Parses a decimal integer
+func Parse(s string) (Number, error) {
+	i, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+	if !ok || i.Sign() < 0 {
+		return Number{}, ErrInvalidNumber
+	}
+
+	return Number{i: i}, nil
+}
+
+// Returns underlying integer, never nil
+func (n Number) int() *big.Int {
+	if n.i == nil {
+		return new(big.Int)
+	}
+
+	return n.i
+}
+
+func (n Number) Add(other Number) Number {
+	return Number{i: new(big.Int).Add(n.int(), other.int())}
+}
+
+// Returns n - other or 0 if other is bigger
+func (n Number) Sub(other Number) Number {
+	if n.Cmp(other) <= 0 {
+		return Number{}
+	}
+
+	return Number{i: new(big.Int).Sub(n.int(), other.int())}
+}
+
+func (n Number) Mul(other Number) Number {
+	return Number{i: new(big.Int).Mul(n.int(), other.int())}
+}
+
+func (n Number) MulUint(value uint64) Number {
+	return n.Mul(New(value))
+}
+
+// Returns n * value truncated to an integer
+func (n Number) MulFloat(value float64) Number {
+	if math.IsNaN(value) || value <= 0.0 {
+		return Number{}
+	}
+
+	return n.mulBigFloat(big.NewFloat(value))
+}
+
+// Returns n * base^exponent truncated to an integer. Unlike math.Pow it does not overflow
+func (n Number) MulFloatPow(base float64, exponent uint64) Number {
+	if math.IsNaN(base) || base <= 0.0 {
+		return Number{}
+	}
+
+	prec := uint(n.int().BitLen() + 128)
+	result := new(big.Float).SetPrec(prec).SetInt64(1)
+	power := new(big.Float).SetPrec(prec).SetFloat64(base)
+	for exponent > 0 {
+		if exponent&1 == 1 {
+			result.Mul(result, power)
+		}
+		power.Mul(power, power)
+		exponent >>= 1
+	}
+
+	return n.mulBigFloat(result)
+}
+
+func (n Number) mulBigFloat(value *big.Float) Number {
+	prec := uint(n.int().BitLen()) + value.Prec() + 64
+	product := new(big.Float).SetPrec(prec).SetInt(n.int())
+	product.Mul(product, value)
+
+	i, _ := product.Int(nil)
+	return Number{i: i}
+}
+
+// Returns n / other rounded down. Division by zero yields 0
+func (n Number) Div(other Number) Number {
+	if other.IsZero() {
+		return Number{}
+	}
+
+	return Number{i: new(big.Int).Quo(n.int(), other.int())}
+}
+
+func (n Number) DivUint(value uint64) Number {
+	return n.Div(New(value))
+}
+
+// Returns square root of n rounded down
+func (n Number) Sqrt() Number {
+	return Number{i: new(big.Int).Sqrt(n.int())}
+}
+
+// Returns the number as uint64 and whether it fits into one
+func (n Number) Uint64() (uint64, bool) {
+	return n.int().Uint64(), n.int().IsUint64()
+}
+
+// Returns -1 if n < other, 0 if n == other and +1 if n > other
+func (n Number) Cmp(other Number) int {
+	return n.int().Cmp(other.int())
+}
+
+func (n Number) IsZero() bool {
+	return n.int().Sign() == 0
+}
+
+// Returns the closest float64, +Inf if the number is too big
+func (n Number) Float64() float64 {
+	f, _ := new(big.Float).SetInt(n.int()).Float64()
+	return f
+}
+
+// Returns the number as plain decimal digits
+func (n Number) String() string {
+	return n.int().String()
+}
+
+// Returns decimal digits separated by commas in groups of three
+func (n Number) Grouped() string {
+	digits := n.String()
+
+	var builder strings.Builder
+	for index, digit := range digits {
+		if index != 0 && (len(digits)-index)%3 == 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteRune(digit)
+	}
+
+	return builder.String()
+}
+
+// Numbers are stored as JSON strings so that any size survives the round trip
+func (n Number) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.String())
+}
+
+// Accepts both JSON strings and plain JSON numbers found in older saves
+func (n *Number) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*n = Number{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		err := json.Unmarshal(data, &s)
+		if err != nil {
+			return err
+		}
+		data = []byte(s)
+	}
+
+	parsed, err := Parse(string(data))
+	if err != nil {
+		// Might be a float written in exponent form
+		f, ok := new(big.Float).SetString(string(data))
+		if !ok || f.Sign() < 0 {
+			return ErrInvalidNumber
+		}
+		parsed.i, _ = f.Int(nil)
+	}
+
+	*n = parsed
+	return nil
+}
@@ -0,0 +1,210 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package bignum
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func mustParse(t *testing.T, s string) Number {
+	t.Helper()
+	n, err := Parse(s)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %s", s, err)
+	}
+	return n
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	tests := []string{
+		"0",
+		"1",
+		"18446744073709551615",
+		"123456789012345678901234567890123456789",
+	}
+
+	for _, test := range tests {
+		original := mustParse(t, test)
+		data, err := json.Marshal(original)
+		if err != nil {
+			t.Fatalf("%s: %s", test, err)
+		}
+		if string(data) != `"`+test+`"` {
+			t.Errorf("%s marshaled as %s", test, data)
+		}
+
+		var decoded Number
+		err = json.Unmarshal(data, &decoded)
+		if err != nil {
+			t.Fatalf("%s: %s", test, err)
+		}
+		if decoded.Cmp(original) != 0 {
+			t.Errorf("%s came back as %s", test, decoded)
+		}
+	}
+
+	var zero Number
+	data, _ := json.Marshal(zero)
+	if string(data) != `"0"` {
+		t.Errorf("zero value marshaled as %s", data)
+	}
+}
+
+func TestUnmarshalLegacy(t *testing.T) {
+	tests := []struct {
+		json string
+		want string
+	}{
+		{`1234`, "1234"},
+		{`18446744073709551615`, "18446744073709551615"},
+		{`1.5e3`, "1500"},
+		{`1e25`, "10000000000000000000000000"},
+		{`12.9`, "12"},
+		{`"42"`, "42"},
+		{`null`, "0"},
+	}
+
+	for _, test := range tests {
+		var n Number
+		err := json.Unmarshal([]byte(test.json), &n)
+		if err != nil {
+			t.Errorf("%s: %s", test.json, err)
+			continue
+		}
+		if n.String() != test.want {
+			t.Errorf("%s: expected %s, got %s", test.json, test.want, n)
+		}
+	}
+
+	for _, invalid := range []string{`-5`, `"-5"`, `"abc"`, `-1e3`, `true`} {
+		var n Number
+		err := json.Unmarshal([]byte(invalid), &n)
+		if err == nil {
+			t.Errorf("%s: expected an error, got %s", invalid, n)
+		}
+	}
+
+	if _, err := Parse("-1"); !errors.Is(err, ErrInvalidNumber) {
+		t.Errorf("negative number parsed: %v", err)
+	}
+}
+
+func TestSubStopsAtZero(t *testing.T) {
+	tests := []struct {
+		a, b, want uint64
+	}{
+		{10, 3, 7},
+		{3, 3, 0},
+		{3, 10, 0},
+		{0, 1, 0},
+	}
+
+	for _, test := range tests {
+		if got := New(test.a).Sub(New(test.b)); got.Cmp(New(test.want)) != 0 {
+			t.Errorf("%d - %d: expected %d, got %s", test.a, test.b, test.want, got)
+		}
+	}
+}
+
+func TestMulFloatPow(t *testing.T) {
+	tests := []struct {
+		n        string
+		base     float64
+		exponent uint64
+		want     string
+	}{
+		{"100", 2.0, 0, "100"},
+		{"100", 2.0, 10, "102400"},
+		{"100", 1.5, 2, "225"},
+		{"1000", 0.5, 3, "125"},
+		{"7", 1.15, 1, "8"},
+		{"1", 10.0, 30, "1000000000000000000000000000000"},
+		{"100", 0.0, 5, "0"},
+		{"100", -2.0, 5, "0"},
+	}
+
+	for _, test := range tests {
+		got := mustParse(t, test.n).MulFloatPow(test.base, test.exponent)
+		if got.String() != test.want {
+			t.Errorf("%s * %v^%d: expected %s, got %s", test.n, test.base, test.exponent, test.want, got)
+		}
+	}
+
+	// Goes far beyond what float64 can hold
+	if huge := New(1).MulFloatPow(2.0, 2000); huge.String() != New(1).MulFloatPow(4.0, 1000).String() {
+		t.Errorf("huge powers disagree")
+	}
+}
+
+func TestCmpAndIsZero(t *testing.T) {
+	var zero Number
+	if !zero.IsZero() || !New(0).IsZero() || New(1).IsZero() {
+		t.Errorf("IsZero is off")
+	}
+
+	big := mustParse(t, "100000000000000000000")
+	tests := []struct {
+		a, b Number
+		want int
+	}{
+		{zero, New(0), 0},
+		{New(1), New(2), -1},
+		{New(2), New(1), 1},
+		{big, New(18446744073709551615), 1},
+		{New(18446744073709551615), big, -1},
+		{big, mustParse(t, "100000000000000000000"), 0},
+	}
+
+	for _, test := range tests {
+		if got := test.a.Cmp(test.b); got != test.want {
+			t.Errorf("%s cmp %s: expected %d, got %d", test.a, test.b, test.want, got)
+		}
+	}
+}
+
+func TestGrouped(t *testing.T) {
+	tests := []struct {
+		n    string
+		want string
+	}{
+		{"0", "0"},
+		{"999", "999"},
+		{"1000", "1,000"},
+		{"123456", "123,456"},
+		{"1234567", "1,234,567"},
+		{"18446744073709551615", "18,446,744,073,709,551,615"},
+	}
+
+	for _, test := range tests {
+		if got := mustParse(t, test.n).Grouped(); got != test.want {
+			t.Errorf("%s: expected %s, got %s", test.n, test.want, got)
+		}
+	}
+}
+
+func TestUint64(t *testing.T) {
+	if value, ok := New(42).Uint64(); !ok || value != 42 {
+		t.Errorf("expected 42, got %d (%v)", value, ok)
+	}
+	if _, ok := New(1).MulFloatPow(2.0, 64).Uint64(); ok {
+		t.Errorf("2^64 fit into uint64")
+	}
+}
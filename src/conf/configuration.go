@@ -19,69 +19,84 @@
 package conf
 
 import (
+	"Unbewohnte/capyclick/logger"
+	"Unbewohnte/capyclick/migration"
+	"Unbewohnte/capyclick/storage"
 	"encoding/json"
-	"io"
-	"os"
 )
 
 const CurrentVersion uint8 = 1
 
 type Configuration struct {
-	ConfigurationVersion uint8   `json:"configurationVersion"`
-	WindowSize           [2]int  `json:"windowSize"`
-	LastWindowPosition   [2]int  `json:"lastWindowPosition"`
-	Volume               float64 `json:"volume"`
+	ConfigurationVersion    uint8               `json:"configurationVersion"`
+	WindowSize              [2]int              `json:"windowSize"`
+	LastWindowPosition      [2]int              `json:"lastWindowPosition"`
+	Volume                  float64             `json:"volume"`
+	OfflineIncomeEfficiency float64             `json:"offlineIncomeEfficiency"`
+	OfflineIncomeCapSeconds uint64              `json:"offlineIncomeCapSeconds"`
+	NumberFormat            string              `json:"numberFormat"`
+	KeyBindings             map[string][]string `json:"keyBindings"`
+	// What to do with saves modified outside the game: "warn", "reset" or "mark"
+	TamperPolicy string `json:"tamperPolicy"`
+	// How often progress is saved while playing, 0 turns periodic saving off
+	AutosaveIntervalSeconds uint64 `json:"autosaveIntervalSeconds"`
 }
 
 // Returns a reasonable default configuration
 func Default() Configuration {
 	return Configuration{
-		ConfigurationVersion: CurrentVersion,
-		WindowSize:           [2]int{640, 280},
-		LastWindowPosition:   [2]int{0, 0},
-		Volume:               1.0,
+		ConfigurationVersion:    CurrentVersion,
+		WindowSize:              [2]int{640, 280},
+		LastWindowPosition:      [2]int{0, 0},
+		Volume:                  1.0,
+		OfflineIncomeEfficiency: 0.5,
+		OfflineIncomeCapSeconds: 8 * 60 * 60,
+		NumberFormat:            "short",
+		KeyBindings:             nil,
+		TamperPolicy:            "mark",
+		AutosaveIntervalSeconds: 30,
 	}
 }
 
-// Tries to retrieve configuration from given json file
-func FromFile(path string) (*Configuration, error) {
-	configFile, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer configFile.Close()
+// Upgrades older configurations to the current version
+var migrations = migration.Registry{
+	Name:         "configuration",
+	VersionField: "configurationVersion",
+	Current:      CurrentVersion,
+	Steps:        map[uint8]migration.Step{},
+}
 
-	confBytes, err := io.ReadAll(configFile)
+// Tries to retrieve configuration kept under the key, upgrading older versions.
+// Falls back to the backup if the value is damaged
+func Load(store storage.Store, key string) (*Configuration, error) {
+	var config Configuration
+	usedBackup, err := storage.LoadWithBackup(store, key, func(data []byte) error {
+		migrated, err := migrations.Apply(data)
+		if err != nil {
+			return err
+		}
+
+		// Start with defaults so that fields missing in older files stay reasonable
+		config = Default()
+		return json.Unmarshal(migrated, &config)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var config Configuration
-	err = json.Unmarshal(confBytes, &config)
-	if err != nil {
-		return nil, err
+	if usedBackup {
+		logger.Warning("[Configuration] \"%s\" is damaged, restored configuration from its backup", key)
 	}
 
 	return &config, nil
 }
 
-// Creates configuration file with given fields
-func Create(path string, conf Configuration) error {
-	configFile, err := os.Create(path)
-	if err != nil {
-		return nil
-	}
-	defer configFile.Close()
-
+// Writes configuration under the key, keeping the previous one as a backup
+func Write(store storage.Store, key string, conf Configuration) error {
 	configJsonBytes, err := json.MarshalIndent(conf, "", " ")
 	if err != nil {
-		return nil
-	}
-
-	_, err = configFile.Write(configJsonBytes)
-	if err != nil {
-		return nil
+		return err
 	}
 
-	return nil
+	return store.Save(key, configJsonBytes)
 }
@@ -0,0 +1,63 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package conf
+
+import (
+	"Unbewohnte/capyclick/migration"
+	"Unbewohnte/capyclick/storage"
+	"errors"
+	"testing"
+)
+
+func TestLoadV1(t *testing.T) {
+	config, err := Load(storage.NewFiles("testdata"), "configuration_v1.json")
+	if err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+
+	if config.WindowSize != [2]int{800, 600} || config.LastWindowPosition != [2]int{100, 50} {
+		t.Errorf("window geometry was not kept: %v %v", config.WindowSize, config.LastWindowPosition)
+	}
+	if config.Volume != 0.4 {
+		t.Errorf("volume is %f, expected 0.4", config.Volume)
+	}
+
+	// Fields added later keep their defaults
+	defaults := Default()
+	if config.NumberFormat != defaults.NumberFormat {
+		t.Errorf("number format is %q, expected %q", config.NumberFormat, defaults.NumberFormat)
+	}
+	if config.OfflineIncomeCapSeconds != defaults.OfflineIncomeCapSeconds {
+		t.Errorf("offline income cap is %d, expected %d", config.OfflineIncomeCapSeconds, defaults.OfflineIncomeCapSeconds)
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	store := storage.NewMemory()
+	err := store.Save("config.json", []byte(`{"configurationVersion": 99}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Load(store, "config.json")
+	var newerErr *migration.NewerVersionError
+	if !errors.As(err, &newerErr) {
+		t.Fatalf("expected a newer version error, got %v", err)
+	}
+}
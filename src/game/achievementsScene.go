@@ -0,0 +1,145 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/achievements"
+	"Unbewohnte/capyclick/input"
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Scrollable list of locked and unlocked achievements
+type AchievementsScene struct {
+	overlayScene
+	scroll int
+	panel  image.Rectangle
+}
+
+func NewAchievementsScene() *AchievementsScene {
+	return &AchievementsScene{
+		scroll: 0,
+	}
+}
+
+func (a *AchievementsScene) Update(game *Game) error {
+	if game.Bindings.JustPressed(input.Back) ||
+		game.Bindings.JustPressed(input.Achievements) ||
+		!a.panel.Empty() && anyPointOutside(justPressedPoints(game.Bindings), a.panel) {
+		// Back to the capybara
+		game.Scenes.Pop()
+		game.PlaySound("boop")
+		return nil
+	}
+
+	_, wheelY := ebiten.Wheel()
+	if game.Bindings.JustPressed(input.Up) || wheelY > 0 {
+		a.scroll--
+	}
+
+	if game.Bindings.JustPressed(input.Down) || wheelY < 0 {
+		a.scroll++
+	}
+
+	if a.scroll > len(achievements.List)-1 {
+		a.scroll = len(achievements.List) - 1
+	}
+
+	if a.scroll < 0 {
+		a.scroll = 0
+	}
+
+	game.Tick(false)
+
+	return nil
+}
+
+func (a *AchievementsScene) Draw(game *Game, screen *ebiten.Image) {
+	margin := screen.Bounds().Dx() / 16
+	panel := image.Rect(
+		margin,
+		game.FontFace.Metrics().Height.Ceil()*3,
+		screen.Bounds().Dx()-margin,
+		screen.Bounds().Dy()-game.FontFace.Metrics().Height.Ceil()*3,
+	)
+	a.panel = panel
+
+	vector.DrawFilledRect(
+		screen,
+		float32(panel.Min.X),
+		float32(panel.Min.Y),
+		float32(panel.Dx()),
+		float32(panel.Dy()),
+		color.RGBA{20, 12, 4, 220},
+		false,
+	)
+
+	lineHeight := game.SmallFontFace.Metrics().Height.Ceil()
+	y := panel.Min.Y + game.FontFace.Metrics().Height.Ceil()
+	text.Draw(
+		screen,
+		fmt.Sprintf(
+			"Achievements %s/%s",
+			game.FormatUint(uint64(len(game.Sim.Save.Achievements))),
+			game.FormatUint(uint64(len(achievements.List))),
+		),
+		game.FontFace,
+		panel.Min.X+10,
+		y,
+		color.White,
+	)
+	y += lineHeight / 2
+
+	barWidth := float32(panel.Dx() - 40)
+	for _, achievement := range achievements.List[a.scroll:] {
+		rowHeight := lineHeight*3 + lineHeight/2
+		if y+rowHeight > panel.Max.Y {
+			break
+		}
+
+		unlocked, when := achievement.Unlocked(game.Sim.Save)
+
+		var clr color.Color = color.Gray{Y: 140}
+		status := "locked"
+		if unlocked {
+			clr = color.White
+			status = when.Format("2006-01-02")
+		}
+
+		y += lineHeight
+		text.Draw(screen, fmt.Sprintf("%s (%s)", achievement.Name, status), game.SmallFontFace, panel.Min.X+10, y, clr)
+		y += lineHeight
+		text.Draw(screen, achievement.Description, game.SmallFontFace, panel.Min.X+20, y, clr)
+
+		// Progress bar
+		progress := float32(achievement.Condition.Progress(game.Sim.Save))
+		if unlocked {
+			progress = 1.0
+		}
+		y += lineHeight / 2
+		vector.DrawFilledRect(screen, float32(panel.Min.X+20), float32(y), barWidth, float32(lineHeight/2), color.Gray{Y: 60}, false)
+		vector.DrawFilledRect(screen, float32(panel.Min.X+20), float32(y), barWidth*progress, float32(lineHeight/2), color.RGBA{255, 165, 0, 255}, false)
+		y += lineHeight
+	}
+}
@@ -0,0 +1,136 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/logger"
+	"Unbewohnte/capyclick/save"
+	"time"
+)
+
+// A copy of the save waiting to be written
+type autosaveRequest struct {
+	profile string
+	save    save.Save
+	reason  string
+	// Receives the result if someone waits for it
+	done chan error
+}
+
+// Writes snapshots of the save in the background so that the game loop never waits for the disk
+type Autosaver struct {
+	requests chan autosaveRequest
+	done     chan struct{}
+	elapsed  time.Duration
+}
+
+// Starts a goroutine that writes requested snapshots into profiles
+func NewAutosaver(profiles *save.Profiles) *Autosaver {
+	autosaver := &Autosaver{
+		// Only the latest snapshot matters, so there is room for a single one
+		requests: make(chan autosaveRequest, 1),
+		done:     make(chan struct{}),
+		elapsed:  0,
+	}
+
+	go func() {
+		defer close(autosaver.done)
+		for request := range autosaver.requests {
+			err := profiles.Store(request.profile, request.save)
+			if request.done != nil {
+				request.done <- err
+				continue
+			}
+			if err != nil {
+				logger.Error("[Autosave] Failed to save progress (%s): %s", request.reason, err)
+				continue
+			}
+			logger.Info("[Autosave] Saved progress (%s)", request.reason)
+		}
+	}()
+
+	return autosaver
+}
+
+// Queues the snapshot, replacing an older one that has not been written yet
+func (a *Autosaver) request(request autosaveRequest) {
+	select {
+	case a.requests <- request:
+	default:
+		select {
+		case <-a.requests:
+		default:
+		}
+		a.requests <- request
+	}
+}
+
+// Writes whatever is queued and stops the goroutine
+func (a *Autosaver) Close() {
+	close(a.requests)
+	<-a.done
+}
+
+// Starts saving progress in the background
+func (g *Game) StartAutosave() {
+	if g.Profiles == nil || g.Autosaver != nil {
+		return
+	}
+
+	g.Autosaver = NewAutosaver(g.Profiles)
+}
+
+// Writes what is left to write and stops background saving
+func (g *Game) StopAutosave() {
+	if g.Autosaver == nil {
+		return
+	}
+
+	g.Autosaver.Close()
+	g.Autosaver = nil
+}
+
+// Saves a snapshot of the active profile in the background
+func (g *Game) Autosave(reason string) {
+	if g.Autosaver == nil || g.ActiveProfile == "" {
+		return
+	}
+
+	g.Sim.Save.LastSavedUnix = uint64(time.Now().Unix())
+	g.Autosaver.request(autosaveRequest{
+		profile: g.ActiveProfile,
+		save:    g.Sim.Save.Clone(),
+		reason:  reason,
+	})
+}
+
+// Counts time and saves once the configured interval passes
+func (g *Game) updateAutosave(delta time.Duration) {
+	if g.Autosaver == nil || g.Config.AutosaveIntervalSeconds == 0 {
+		return
+	}
+
+	g.Autosaver.elapsed += delta
+	if g.Autosaver.elapsed < time.Duration(g.Config.AutosaveIntervalSeconds)*time.Second {
+		return
+	}
+
+	g.Autosaver.elapsed = 0
+	g.Autosave("interval")
+}
@@ -19,59 +19,109 @@
 package game
 
 import (
+	"Unbewohnte/capyclick/animation"
+	"Unbewohnte/capyclick/tween"
+	"fmt"
+	"math"
+	"time"
+
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	capybaraSquishPerClick float64       = 0.5
+	capybaraMaxSquish      float64       = 2.0
+	capybaraBounceTime     time.Duration = time.Millisecond * 600
+	capybaraSwayAngle      float64       = 0.03
+	capybaraSwayTime       time.Duration = time.Second
 )
 
 type Capybara struct {
 	Sprite *Sprite
+	// Level the idle clip is played for
+	level uint32
+	// Springs the squish back after clicks
+	bounce *tween.Tween
+	// Rocks the capybara side to side
+	sway tween.Animation
 }
 
 func NewCapybara(sprite *Sprite) *Capybara {
+	theta := &sprite.Animation.Theta
 	return &Capybara{
 		Sprite: sprite,
+		level:  0,
+		bounce: nil,
+		sway: tween.Repeat(tween.Sequence(
+			tween.To(theta, capybaraSwayAngle, capybaraSwayTime, tween.InOutQuad),
+			tween.To(theta, -capybaraSwayAngle, capybaraSwayTime, tween.InOutQuad),
+		), 0),
 	}
 }
 
-func (c *Capybara) Update() {
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
-		len(inpututil.AppendJustPressedTouchIDs(nil)) != 0 {
-		c.Sprite.Animation.Squish += 0.5
+// Returns the clip of given action for the capybara of given level or nil if there is none
+func capybaraClip(library animation.Library, level uint32, action string) *animation.Clip {
+	if level < 1 {
+		level = 1
+	} else if level > 3 {
+		level = 3
+	}
+
+	return library[fmt.Sprintf("capybara_%d_%s", level, action)]
+}
+
+// Munches on mandarins
+func (c *Capybara) Chew(game *Game) {
+	c.Sprite.Play(capybaraClip(game.Animations, c.level, "chew"))
+}
+
+func (c *Capybara) Update(game *Game, clicked bool, delta time.Duration) {
+	level := game.Sim.Save.Level
+	switch {
+	case clicked:
+		c.Sprite.Play(capybaraClip(game.Animations, level, "react"))
+	case level != c.level || c.Sprite.Animator.Clip() == nil || c.Sprite.Animator.Finished():
+		// Back to idling, looks of the capybara depend on the level
+		c.Sprite.Play(capybaraClip(game.Animations, level, "idle"))
 	}
+	c.level = level
 
-	// Capybara Animation
-	capyAniData := &c.Sprite.Animation
-	if capyAniData.Theta >= 0.03 {
-		capyAniData.BounceDirectionFlag = false
-	} else if capyAniData.Theta <= -0.03 {
-		capyAniData.BounceDirectionFlag = true
+	for _, event := range c.Sprite.UpdateAnimation(delta) {
+		if event == "chomp" {
+			game.PlaySound("orange_put")
+		}
 	}
 
-	if capyAniData.Squish >= 0 {
-		capyAniData.Squish -= 0.05
+	if clicked {
+		// Squish piles up with quick clicks and springs back after
+		squish := &c.Sprite.Animation.Squish
+		*squish = math.Min(*squish+capybaraSquishPerClick, capybaraMaxSquish)
+		c.bounce = tween.To(squish, 0.0, capybaraBounceTime, tween.OutElastic)
 	}
 
-	if capyAniData.BounceDirectionFlag {
-		capyAniData.Theta += 0.001
-	} else {
-		capyAniData.Theta -= 0.001
+	if c.bounce != nil {
+		c.bounce.Update(delta)
 	}
+	c.sway.Update(delta)
 }
 
 func (c *Capybara) Draw(screen *ebiten.Image, level uint32) {
-	// Capybara
-	switch level {
-	case 1:
-		c.Sprite.ChangeImageByName("capybara_1.png")
-	case 2:
-		c.Sprite.ChangeImageByName("capybara_2.png")
-	case 3:
-		c.Sprite.ChangeImageByName("capybara_3.png")
-	default:
-		c.Sprite.ChangeImageByName("capybara_3.png")
+	// Capybara, static images are there in case clips are missing
+	if c.Sprite.Animator.Clip() == nil {
+		switch level {
+		case 1:
+			c.Sprite.ChangeImageByName("capybara_1.png")
+		case 2:
+			c.Sprite.ChangeImageByName("capybara_2.png")
+		case 3:
+			c.Sprite.ChangeImageByName("capybara_3.png")
+		default:
+			c.Sprite.ChangeImageByName("capybara_3.png")
+		}
 	}
 
 	op := &ebiten.DrawImageOptions{}
+	c.Sprite.applyFrame(op)
 	capybaraBounds := c.Sprite.Img.Bounds()
 	scale := float64(screen.Bounds().Dx()) / float64(capybaraBounds.Dx()) / 2.0
 	c.Sprite.Scale = scale
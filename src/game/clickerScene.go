@@ -0,0 +1,292 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/input"
+	"Unbewohnte/capyclick/physics"
+	"Unbewohnte/capyclick/prestige"
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// The clicker itself: capybara, mandarin rains and the HUD
+type ClickerScene struct {
+	baseScene
+	shopButton         image.Rectangle
+	prestigeButton     image.Rectangle
+	achievementsButton image.Rectangle
+	points             RollingNumber
+}
+
+func NewClickerScene() *ClickerScene {
+	return &ClickerScene{}
+}
+
+func (c *ClickerScene) Update(g *Game) error {
+	if g.Bindings.JustPressed(input.Pause) {
+		// Pause
+		g.Scenes.Push(NewPauseScene())
+		g.PlaySound("boop")
+		return nil
+	}
+
+	if g.Bindings.JustPressed(input.VolumeDown) {
+		// Decrease volume
+		g.DecreaseVolume(0.2)
+	}
+
+	if g.Bindings.JustPressed(input.VolumeUp) {
+		// Increase volume
+		g.IncreaseVolume(0.2)
+	}
+
+	pressed := justPressedPoints(g.Bindings)
+	if g.Bindings.JustPressed(input.Shop) || anyPointIn(pressed, c.shopButton) {
+		// Open the shop
+		g.Scenes.Push(NewShopScene())
+		g.PlaySound("boop")
+		return nil
+	}
+
+	if g.Bindings.JustPressed(input.Achievements) || anyPointIn(pressed, c.achievementsButton) {
+		// Open achievements list
+		g.Scenes.Push(NewAchievementsScene())
+		g.PlaySound("boop")
+		return nil
+	}
+
+	if prestige.CanPrestige(g.Sim.Save) &&
+		(g.Bindings.JustPressed(input.Prestige) || anyPointIn(pressed, c.prestigeButton)) {
+		// Ask whether to be reborn
+		g.Scenes.Push(NewPrestigeScene())
+		g.PlaySound("boop")
+		return nil
+	}
+
+	clicked := false
+	if g.Bindings.JustPressed(input.Click) || len(inpututil.AppendJustPressedTouchIDs(nil)) != 0 {
+		// Click!
+		clicked = true
+		spots := c.clickSpots(g, pressed)
+		for _, spot := range spots {
+			g.Effects.Sparkle(spot.X, spot.Y)
+		}
+		g.ClickSpot = spots[0]
+	}
+
+	if g.MandarinRain.InProgress {
+		// Grab mandarins or the box
+		if in, ok := g.Bindings.JustPressedOn(input.Click, input.DeviceMouse); ok {
+			physical := g.MandarinRain.PhysicalAt(ebiten.CursorPosition())
+			if physical != nil {
+				s := NewStroke(&MouseStrokeSource{Button: in}, physical)
+				g.Strokes[s] = struct{}{}
+			}
+		}
+
+		if in, ok := g.Bindings.JustPressedOn(input.Click, input.DeviceGamepad); ok && g.Cursor.Visible {
+			physical := g.MandarinRain.PhysicalAt(g.Cursor.Position())
+			if physical != nil {
+				s := NewStroke(&GamepadStrokeSource{Cursor: &g.Cursor, Button: in}, physical)
+				g.Strokes[s] = struct{}{}
+			}
+		}
+
+		g.TouchIDs = inpututil.AppendJustPressedTouchIDs(g.TouchIDs[:0])
+		for _, id := range g.TouchIDs {
+			physical := g.MandarinRain.PhysicalAt(ebiten.TouchPosition(id))
+			if physical != nil {
+				s := NewStroke(&TouchStrokeSource{id}, physical)
+				g.Strokes[s] = struct{}{}
+			}
+		}
+	}
+
+	g.Tick(clicked)
+	c.points.Update(g.Sim.Save.Points, g.FrameDelta)
+
+	return nil
+}
+
+// Returns where the screen was clicked or the top of the capybara for keys and buttons
+func (c *ClickerScene) clickSpots(g *Game, pressed []image.Point) []physics.Vec2 {
+	if len(pressed) == 0 && g.Cursor.Visible {
+		x, y := g.Cursor.Position()
+		return []physics.Vec2{physics.V(float64(x), float64(y))}
+	}
+
+	if len(pressed) == 0 {
+		bounds := g.Capybara.Sprite.RealBounds()
+		return []physics.Vec2{physics.V(
+			g.Capybara.Sprite.X+float64(bounds.Dx())/2.0,
+			g.Capybara.Sprite.Y+float64(bounds.Dy())/4.0,
+		)}
+	}
+
+	spots := make([]physics.Vec2, 0, len(pressed))
+	for _, point := range pressed {
+		spots = append(spots, physics.V(float64(point.X), float64(point.Y)))
+	}
+
+	return spots
+}
+
+func (c *ClickerScene) Draw(g *Game, screen *ebiten.Image) {
+	g.DrawWorld(screen)
+
+	// Points
+	msg := fmt.Sprintf("Points: %s", g.FormatNumber(c.points.Value()))
+	text.Draw(
+		screen,
+		msg,
+		g.FontFace,
+		10,
+		g.FontFace.Metrics().Height.Ceil(),
+		color.White,
+	)
+	// Income floats up right past the counter
+	g.FloatingTexts.PointsAnchor = physics.V(
+		float64(10+text.BoundString(g.FontFace, msg).Dx()+text.BoundString(g.FontFace, "+0").Dx()),
+		float64(g.FontFace.Metrics().Height.Ceil()*2),
+	)
+
+	// Level
+	msg = fmt.Sprintf(
+		"Level: %s (+%s)",
+		g.FormatUint(uint64(g.Sim.Save.Level)),
+		g.FormatNumber(g.Sim.PointsToNextLevel()),
+	)
+	text.Draw(
+		screen,
+		msg,
+		g.FontFace,
+		10,
+		g.FontFace.Metrics().Height.Ceil()*2,
+		color.White,
+	)
+
+	// Shop button
+	msg = fmt.Sprintf("Shop (%s)", g.Bindings.Label(input.Shop))
+	bounds := text.BoundString(g.FontFace, msg)
+	c.shopButton = image.Rect(
+		screen.Bounds().Dx()-bounds.Dx()-20,
+		0,
+		screen.Bounds().Dx(),
+		g.FontFace.Metrics().Height.Ceil()+10,
+	)
+	text.Draw(
+		screen,
+		msg,
+		g.FontFace,
+		c.shopButton.Min.X+10,
+		g.FontFace.Metrics().Height.Ceil(),
+		color.White,
+	)
+
+	// Rebirth button
+	c.prestigeButton = image.Rectangle{}
+	if prestige.CanPrestige(g.Sim.Save) {
+		msg = fmt.Sprintf("Rebirth (%s)", g.Bindings.Label(input.Prestige))
+		bounds = text.BoundString(g.FontFace, msg)
+		c.prestigeButton = image.Rect(
+			screen.Bounds().Dx()-bounds.Dx()-20,
+			c.shopButton.Max.Y,
+			screen.Bounds().Dx(),
+			c.shopButton.Max.Y+g.FontFace.Metrics().Height.Ceil()+10,
+		)
+		text.Draw(
+			screen,
+			msg,
+			g.FontFace,
+			c.prestigeButton.Min.X+10,
+			c.prestigeButton.Min.Y+g.FontFace.Metrics().Height.Ceil(),
+			color.RGBA{255, 215, 0, 255},
+		)
+	}
+
+	// Golden mandarins
+	if !g.Sim.Save.GoldenMandarins.IsZero() {
+		msg = fmt.Sprintf("Golden mandarins: %s (x%.1f)", g.FormatNumber(g.Sim.Save.GoldenMandarins), prestige.Multiplier(g.Sim.Save))
+		text.Draw(
+			screen,
+			msg,
+			g.SmallFontFace,
+			10,
+			g.FontFace.Metrics().Height.Ceil()*2+g.SmallFontFace.Metrics().Height.Ceil(),
+			color.RGBA{255, 215, 0, 255},
+		)
+	}
+
+	// Times Clicked
+	msg = fmt.Sprintf("Clicks: %s", g.FormatUint(g.Sim.Save.TimesClicked))
+	text.Draw(
+		screen,
+		msg,
+		g.FontFace,
+		10,
+		screen.Bounds().Dy()-g.FontFace.Metrics().Height.Ceil()*2,
+		color.White,
+	)
+
+	// Achievements button
+	msg = fmt.Sprintf("Awards (%s)", g.Bindings.Label(input.Achievements))
+	bounds = text.BoundString(g.FontFace, msg)
+	c.achievementsButton = image.Rect(
+		screen.Bounds().Dx()-bounds.Dx()-20,
+		screen.Bounds().Dy()-g.FontFace.Metrics().Height.Ceil()*3,
+		screen.Bounds().Dx(),
+		screen.Bounds().Dy()-g.FontFace.Metrics().Height.Ceil()*2+10,
+	)
+	text.Draw(
+		screen,
+		msg,
+		g.FontFace,
+		c.achievementsButton.Min.X+10,
+		screen.Bounds().Dy()-g.FontFace.Metrics().Height.Ceil()*2,
+		color.White,
+	)
+
+	// Volume
+	msg = fmt.Sprintf(
+		"Volume: %d%% (%s or %s)",
+		int(g.Config.Volume*100.0),
+		g.Bindings.Label(input.VolumeDown),
+		g.Bindings.Label(input.VolumeUp),
+	)
+	text.Draw(
+		screen,
+		msg,
+		g.FontFace,
+		10,
+		screen.Bounds().Dy()-g.FontFace.Metrics().Height.Ceil(),
+		color.White,
+	)
+
+	// Earned points
+	g.FloatingTexts.Draw(screen, g)
+
+	// Gamepad cursor goes over everything
+	g.Cursor.Draw(screen)
+}
@@ -0,0 +1,175 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/input"
+	"Unbewohnte/capyclick/logger"
+	"errors"
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// How long to wait for a new input before giving up
+const captureTimeout time.Duration = 5 * time.Second
+
+// Lists every action with its inputs and lets the player rebind them
+type ControlsScene struct {
+	baseScene
+	menu *Menu
+	// Action waiting for a new input, empty when not rebinding
+	capturing     input.Action
+	captureLeft   time.Duration
+	message       string
+	messageIsFail bool
+}
+
+// Returns all inputs of the action joined together
+func bindingsLabel(game *Game, action input.Action) string {
+	inputs := game.Bindings[action]
+	if len(inputs) == 0 {
+		return "unbound"
+	}
+
+	labels := make([]string, 0, len(inputs))
+	for _, in := range inputs {
+		labels = append(labels, in.Label())
+	}
+	return strings.Join(labels, ", ")
+}
+
+func NewControlsScene() *ControlsScene {
+	scene := &ControlsScene{}
+
+	var items []MenuItem
+	for _, info := range input.Actions {
+		info := info
+		items = append(items, MenuItem{
+			Label: func(game *Game) string {
+				if scene.capturing == info.Action {
+					return fmt.Sprintf("%s: press an input...", info.Name)
+				}
+				return fmt.Sprintf("%s: %s", info.Name, bindingsLabel(game, info.Action))
+			},
+			Action: func(game *Game) error {
+				scene.capturing = info.Action
+				scene.captureLeft = captureTimeout
+				scene.message = ""
+				return nil
+			},
+		})
+	}
+
+	items = append(items,
+		MenuItem{
+			Label: staticLabel("Reset to defaults"),
+			Action: func(game *Game) error {
+				game.ResetBindings()
+				scene.message = "Controls were reset"
+				scene.messageIsFail = false
+				return nil
+			},
+		},
+		MenuItem{
+			Label: staticLabel("Back"),
+			Action: func(game *Game) error {
+				game.Scenes.Pop()
+				return nil
+			},
+		},
+	)
+
+	scene.menu = NewMenu(items...)
+	return scene
+}
+
+func (c *ControlsScene) Update(game *Game) error {
+	if c.capturing != "" {
+		c.captureLeft -= game.FrameDelta
+		if c.captureLeft <= 0 {
+			c.capturing = ""
+			c.message = "Nothing was pressed, binding kept"
+			c.messageIsFail = false
+			return nil
+		}
+
+		in, ok := input.JustPressedInput()
+		if !ok {
+			return nil
+		}
+
+		action := c.capturing
+		c.capturing = ""
+		err := game.Rebind(action, in)
+		if err != nil {
+			var conflict *input.ConflictError
+			if !errors.As(err, &conflict) {
+				logger.Warning("[Controls] Failed to bind %s to %s: %s", in, action, err)
+			}
+			c.message = err.Error()
+			c.messageIsFail = true
+			game.PlaySound("boop")
+			return nil
+		}
+
+		info, _ := input.Info(action)
+		c.message = fmt.Sprintf("%s is now bound to \"%s\"", in.Label(), info.Name)
+		c.messageIsFail = false
+		game.PlaySound("boop")
+		return nil
+	}
+
+	if game.Bindings.JustPressed(input.Back) {
+		game.Scenes.Pop()
+		game.PlaySound("boop")
+		return nil
+	}
+
+	return c.menu.Update(game)
+}
+
+func (c *ControlsScene) Draw(game *Game, screen *ebiten.Image) {
+	game.DrawWorld(screen)
+
+	panel := menuPanel(screen)
+	drawPanel(screen, panel)
+
+	x := panel.Min.X + 20
+	y := panel.Min.Y + game.FontFace.Metrics().Height.Ceil()
+	text.Draw(screen, "Controls", game.FontFace, x, y, color.White)
+
+	y += game.SmallFontFace.Metrics().Height.Ceil()
+	if c.message != "" {
+		var clr color.Color = color.Gray{Y: 160}
+		if c.messageIsFail {
+			clr = color.RGBA{255, 80, 80, 255}
+		}
+		text.Draw(screen, c.message, game.SmallFontFace, x, y, clr)
+	} else {
+		text.Draw(screen, "Choose an action to bind a new input to it", game.SmallFontFace, x, y, color.Gray{Y: 160})
+	}
+
+	c.menu.Face = game.SmallFontFace
+	c.menu.Draw(game, screen, x, y+game.SmallFontFace.Metrics().Height.Ceil()/2)
+}
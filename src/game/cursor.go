@@ -0,0 +1,87 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/input"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// How many seconds it takes the cursor to cross the whole screen with the stick fully tilted
+const cursorCrossSeconds float64 = 1.5
+
+// Cursor moved with a gamepad's analog stick
+type VirtualCursor struct {
+	X       float64
+	Y       float64
+	Visible bool
+	// Last known mouse position, moving the mouse hides the cursor
+	mouseX int
+	mouseY int
+}
+
+// Returns cursor position in screen coordinates
+func (c *VirtualCursor) Position() (int, int) {
+	return int(c.X), int(c.Y)
+}
+
+// Moves the cursor according to the stick, keeping it inside the screen
+func (c *VirtualCursor) Update(screenWidth int, screenHeight int, delta time.Duration) {
+	mouseX, mouseY := ebiten.CursorPosition()
+	if mouseX != c.mouseX || mouseY != c.mouseY {
+		c.mouseX, c.mouseY = mouseX, mouseY
+		c.Visible = false
+	}
+
+	if !input.GamepadConnected() {
+		c.Visible = false
+		return
+	}
+
+	x, y := input.LeftStick()
+	if x == 0.0 && y == 0.0 {
+		return
+	}
+
+	if !c.Visible {
+		// Appear in the middle of the screen
+		c.Visible = true
+		c.X = float64(screenWidth) / 2.0
+		c.Y = float64(screenHeight) / 2.0
+	}
+
+	travelled := delta.Seconds() / cursorCrossSeconds
+	c.X = math.Max(0.0, math.Min(float64(screenWidth-1), c.X+x*float64(screenWidth)*travelled))
+	c.Y = math.Max(0.0, math.Min(float64(screenHeight-1), c.Y+y*float64(screenHeight)*travelled))
+}
+
+func (c *VirtualCursor) Draw(screen *ebiten.Image) {
+	if !c.Visible {
+		return
+	}
+
+	vector.StrokeCircle(screen, float32(c.X), float32(c.Y), 12, 3, color.Black, true)
+	vector.StrokeCircle(screen, float32(c.X), float32(c.Y), 10, 2, color.RGBA{255, 165, 0, 255}, true)
+	vector.DrawFilledCircle(screen, float32(c.X), float32(c.Y), 2, color.White, true)
+}
@@ -0,0 +1,173 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/particles"
+	"Unbewohnte/capyclick/physics"
+	"Unbewohnte/capyclick/resources"
+	"Unbewohnte/capyclick/tween"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// Keeps frame time steady however many effects pile up
+	maxParticles       int           = 2000
+	sparklesPerClick   int           = 12
+	fireworkBursts     int           = 4
+	fireworkBurstSize  int           = 80
+	fireworkBurstDelay time.Duration = time.Millisecond * 250
+	juicePerOrange     int           = 16
+)
+
+var fireworkColors = [][]color.RGBA{
+	{{255, 165, 0, 255}, {255, 220, 120, 255}},
+	{{255, 90, 90, 255}, {255, 180, 180, 255}},
+	{{120, 220, 120, 255}, {220, 255, 200, 255}},
+	{{130, 170, 255, 255}, {220, 230, 255, 255}},
+}
+
+// Particle effects of the world
+type Effects struct {
+	Particles *particles.System
+	sparkles  particles.Emitter
+	firework  particles.Emitter
+	juice     particles.Emitter
+	// Sets firework bursts off one after another
+	timeline tween.Group
+}
+
+func NewEffects() *Effects {
+	return &Effects{
+		Particles: particles.NewSystem(maxParticles),
+		sparkles: particles.Emitter{
+			Radius:         4.0,
+			Lifetime:       time.Millisecond * 500,
+			LifetimeSpread: time.Millisecond * 150,
+			Speed:          220.0,
+			SpeedSpread:    80.0,
+			Direction:      -math.Pi / 2.0,
+			Spread:         math.Pi * 1.5,
+			Gravity:        physics.V(0.0, 400.0),
+			Fade:           particles.Curve{From: 1.0, To: 0.0, Ease: tween.InQuad},
+			Scale:          particles.Curve{From: 1.0, To: 0.3, Ease: tween.Linear},
+			Images:         []string{"particle_sparkle.png"},
+			Colors:         []color.RGBA{{255, 255, 255, 255}, {255, 240, 160, 255}},
+		},
+		firework: particles.Emitter{
+			Radius:         2.0,
+			Lifetime:       time.Millisecond * 1200,
+			LifetimeSpread: time.Millisecond * 300,
+			Speed:          260.0,
+			SpeedSpread:    60.0,
+			Spread:         2.0 * math.Pi,
+			Gravity:        physics.V(0.0, 120.0),
+			Fade:           particles.Curve{From: 1.0, To: 0.0, Ease: tween.InCubic},
+			Scale:          particles.Curve{From: 1.2, To: 0.5, Ease: tween.OutQuad},
+			Images:         []string{"particle_sparkle.png", "particle_drop.png"},
+		},
+		juice: particles.Emitter{
+			Radius:         6.0,
+			Lifetime:       time.Millisecond * 600,
+			LifetimeSpread: time.Millisecond * 200,
+			Speed:          180.0,
+			SpeedSpread:    70.0,
+			Direction:      -math.Pi / 2.0,
+			Spread:         math.Pi,
+			Gravity:        physics.V(0.0, 700.0),
+			Fade:           particles.Curve{From: 1.0, To: 0.0, Ease: tween.InQuad},
+			Scale:          particles.Curve{From: 1.0, To: 0.6, Ease: tween.Linear},
+			Images:         []string{"particle_drop.png"},
+			Colors:         []color.RGBA{{255, 140, 0, 255}, {255, 180, 40, 255}, {255, 210, 90, 255}},
+		},
+		timeline: tween.Group{},
+	}
+}
+
+// Sprinkles sparkles where the capybara was clicked
+func (e *Effects) Sparkle(x float64, y float64) {
+	e.sparkles.Position = physics.V(x, y)
+	e.Particles.Burst(&e.sparkles, sparklesPerClick)
+}
+
+// Sets off a few firework bursts over the upper part of the screen
+func (e *Effects) Firework(screen image.Rectangle) {
+	steps := make([]tween.Animation, 0, fireworkBursts*2)
+	for burst := 0; burst < fireworkBursts; burst++ {
+		colors := fireworkColors[burst%len(fireworkColors)]
+		steps = append(steps, tween.Call(func() {
+			e.firework.Position = physics.V(
+				float64(screen.Min.X)+float64(screen.Dx())*(0.2+rand.Float64()*0.6),
+				float64(screen.Min.Y)+float64(screen.Dy())*(0.1+rand.Float64()*0.3),
+			)
+			e.firework.Colors = colors
+			e.Particles.Burst(&e.firework, fireworkBurstSize)
+		}))
+		steps = append(steps, tween.Delay(fireworkBurstDelay))
+	}
+
+	e.timeline.Add(tween.Sequence(steps...))
+}
+
+// Splashes juice where an orange went into the box
+func (e *Effects) Splash(x float64, y float64) {
+	e.juice.Position = physics.V(x, y)
+	e.Particles.Burst(&e.juice, juicePerOrange)
+}
+
+// Moves effects on by delta
+func (e *Effects) Update(delta time.Duration) {
+	e.timeline.Update(delta)
+	e.Particles.Update(delta)
+}
+
+// Removes all effects
+func (e *Effects) Clear() {
+	e.timeline.Clear()
+	e.Particles.Clear()
+}
+
+func (e *Effects) Draw(screen *ebiten.Image) {
+	// Particle images are a few pixels big
+	pixel := math.Max(1.0, float64(screen.Bounds().Dx())/300.0)
+
+	op := &ebiten.DrawImageOptions{}
+	for _, particle := range e.Particles.Particles() {
+		img := resources.Image(particle.Image)
+		if img == nil {
+			continue
+		}
+
+		// Particle images come from the atlas, so these draws are batched
+		op.GeoM.Reset()
+		op.ColorScale.Reset()
+		op.GeoM.Translate(-float64(img.Bounds().Dx())/2.0, -float64(img.Bounds().Dy())/2.0)
+		op.GeoM.Scale(pixel*particle.Scale(), pixel*particle.Scale())
+		op.GeoM.Translate(particle.Position.X, particle.Position.Y)
+		op.ColorScale.ScaleWithColor(particle.Color)
+		op.ColorScale.ScaleAlpha(float32(particle.Alpha()))
+		screen.DrawImage(img, op)
+	}
+}
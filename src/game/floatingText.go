@@ -0,0 +1,143 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/bignum"
+	"Unbewohnte/capyclick/physics"
+	"Unbewohnte/capyclick/tween"
+	"image/color"
+	"math/rand"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+const (
+	floatingTextLife time.Duration = time.Millisecond * 1200
+	// Pixels a text rises over its life
+	floatingTextRise  float64 = 70.0
+	floatingTextDrift float64 = 16.0
+	// Earnings this close in time and space add up in a single text
+	floatingTextMergeTime     time.Duration = time.Millisecond * 300
+	floatingTextMergeDistance float64       = 48.0
+	maxFloatingTexts          int           = 32
+)
+
+var (
+	clickTextColor  = color.RGBA{255, 165, 0, 255}
+	incomeTextColor = color.RGBA{170, 255, 140, 255}
+)
+
+// "+N" rising from where points were earned
+type floatingText struct {
+	origin physics.Vec2
+	drift  float64
+	amount bignum.Number
+	label  string
+	color  color.RGBA
+	age    time.Duration
+}
+
+// Returns how much of its life the text has lived in [0.0; 1.0]
+func (f *floatingText) life() float64 {
+	if f.age >= floatingTextLife {
+		return 1.0
+	}
+
+	return float64(f.age) / float64(floatingTextLife)
+}
+
+// Earned points floating up and fading out
+type FloatingTexts struct {
+	texts []floatingText
+	// Where income texts rise from, next to the points counter
+	PointsAnchor physics.Vec2
+}
+
+// Shows amount at the position, merging it into a fresh text of the same color nearby
+func (f *FloatingTexts) Spawn(game *Game, x float64, y float64, amount bignum.Number, clr color.RGBA) {
+	position := physics.V(x, y)
+	for index := len(f.texts) - 1; index >= 0; index-- {
+		existing := &f.texts[index]
+		if existing.color != clr || existing.age > floatingTextMergeTime ||
+			existing.origin.Sub(position).Len() > floatingTextMergeDistance {
+			continue
+		}
+
+		// Add up instead of piling texts on top of each other. The text keeps its age,
+		// so it rises and fades however fast points come in
+		existing.amount = existing.amount.Add(amount)
+		existing.label = "+" + game.FormatNumber(existing.amount)
+		return
+	}
+
+	if len(f.texts) >= maxFloatingTexts {
+		// Make room by dropping the oldest one
+		copy(f.texts, f.texts[1:])
+		f.texts = f.texts[:len(f.texts)-1]
+	}
+
+	f.texts = append(f.texts, floatingText{
+		origin: position,
+		drift:  (rand.Float64()*2.0 - 1.0) * floatingTextDrift,
+		amount: amount,
+		label:  "+" + game.FormatNumber(amount),
+		color:  clr,
+		age:    0,
+	})
+}
+
+// Ages texts by delta and drops the faded ones
+func (f *FloatingTexts) Update(delta time.Duration) {
+	alive := f.texts[:0]
+	for _, floating := range f.texts {
+		floating.age += delta
+		if floating.age < floatingTextLife {
+			alive = append(alive, floating)
+		}
+	}
+	f.texts = alive
+}
+
+// Removes all texts
+func (f *FloatingTexts) Clear() {
+	f.texts = f.texts[:0]
+}
+
+func (f *FloatingTexts) Draw(screen *ebiten.Image, game *Game) {
+	// Glyphs of a face come from one cached texture, so all texts end up in a few batched draws
+	op := &ebiten.DrawImageOptions{}
+	for index := range f.texts {
+		floating := &f.texts[index]
+		life := floating.life()
+		width := float64(text.BoundString(game.FontFace, floating.label).Dx())
+
+		op.GeoM.Reset()
+		op.ColorScale.Reset()
+		op.GeoM.Translate(
+			floating.origin.X-width/2.0+floating.drift*tween.OutQuad(life),
+			floating.origin.Y-floatingTextRise*tween.OutCubic(life),
+		)
+		op.ColorScale.ScaleWithColor(floating.color)
+		op.ColorScale.ScaleAlpha(float32(1.0 - tween.InQuad(life)))
+		text.DrawWithOptions(screen, floating.label, game.FontFace, op)
+	}
+}
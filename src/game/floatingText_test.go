@@ -0,0 +1,78 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/bignum"
+	"Unbewohnte/capyclick/conf"
+	"testing"
+	"time"
+)
+
+func TestFloatingTextsMergeAndAgeOut(t *testing.T) {
+	game := &Game{Config: conf.Default()}
+	var texts FloatingTexts
+
+	// Clicks come a lot faster than the merge window
+	step := floatingTextMergeTime / 6
+	for elapsed := time.Duration(0); elapsed < floatingTextMergeTime; elapsed += step {
+		texts.Spawn(game, 100.0, 100.0, bignum.New(2), clickTextColor)
+		texts.Update(step)
+	}
+
+	if len(texts.texts) != 1 {
+		t.Fatalf("expected clicks to merge into 1 text, got %d", len(texts.texts))
+	}
+	if texts.texts[0].amount.Cmp(bignum.New(12)) != 0 || texts.texts[0].label != "+12" {
+		t.Errorf("merged text shows %s", texts.texts[0].label)
+	}
+	if texts.texts[0].life() <= 0.0 {
+		t.Errorf("merged text did not age")
+	}
+
+	// Merging stops once the text is older than the window
+	texts.Update(step)
+	texts.Spawn(game, 100.0, 100.0, bignum.New(1), clickTextColor)
+	if len(texts.texts) != 2 {
+		t.Errorf("expected a fresh text after the window, got %d texts", len(texts.texts))
+	}
+
+	texts.Update(floatingTextLife - floatingTextMergeTime)
+	if len(texts.texts) != 1 {
+		t.Errorf("merged text did not age out, %d texts left", len(texts.texts))
+	}
+	texts.Update(floatingTextLife)
+	if len(texts.texts) != 0 {
+		t.Errorf("%d texts outlived their life", len(texts.texts))
+	}
+}
+
+func TestFloatingTextsStayBelowCap(t *testing.T) {
+	game := &Game{Config: conf.Default()}
+	var texts FloatingTexts
+
+	for i := 0; i < maxFloatingTexts*3; i++ {
+		// Far apart, so nothing merges
+		texts.Spawn(game, float64(i)*floatingTextMergeDistance*2.0, 0.0, bignum.New(1), incomeTextColor)
+	}
+
+	if len(texts.texts) != maxFloatingTexts {
+		t.Errorf("expected %d texts, got %d", maxFloatingTexts, len(texts.texts))
+	}
+}
@@ -19,46 +19,105 @@
 package game
 
 import (
+	"Unbewohnte/capyclick/animation"
+	"Unbewohnte/capyclick/bignum"
 	"Unbewohnte/capyclick/conf"
+	"Unbewohnte/capyclick/input"
 	"Unbewohnte/capyclick/logger"
+	"Unbewohnte/capyclick/numfmt"
+	"Unbewohnte/capyclick/physics"
 	"Unbewohnte/capyclick/resources"
 	"Unbewohnte/capyclick/save"
+	"Unbewohnte/capyclick/sim"
+	"Unbewohnte/capyclick/storage"
+	"Unbewohnte/capyclick/timestep"
 	"Unbewohnte/capyclick/util"
-	"fmt"
 	"image/color"
-	"path/filepath"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
-	"github.com/hajimehoshi/ebiten/v2/text"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
 )
 
+const (
+	// How much game time a single world step covers
+	StepDuration time.Duration = time.Second / 60
+	// The most world steps run in a single frame, the rest is skipped after long hiccups
+	maxStepsPerFrame int = 10
+)
+
 type Game struct {
-	WorkingDir          string
-	Config              conf.Configuration
-	Save                save.Save
-	AudioPlayers        map[string]*audio.Player
-	FontFace            font.Face
-	PassiveIncomeTicker int
-	Screen              *ebiten.Image
-	TouchIDs            []ebiten.TouchID
-	Strokes             map[*Stroke]struct{}
-	Capybara            *Capybara
-	Background          *Sprite
-	MandarinRain        *MandarinRain
+	WorkingDir    string
+	Store         storage.Store
+	Config        conf.Configuration
+	Animations    animation.Library
+	Sim           sim.State
+	AudioPlayers  map[string]*audio.Player
+	FontFace      font.Face
+	SmallFontFace font.Face
+	Screen        *ebiten.Image
+	TouchIDs      []ebiten.TouchID
+	Strokes       map[*Stroke]struct{}
+	Capybara      *Capybara
+	Background    *Sprite
+	MandarinRain  *MandarinRain
+	Effects       *Effects
+	FloatingTexts FloatingTexts
+	// Where the latest click landed, its earnings float up from there
+	ClickSpot     physics.Vec2
+	Toasts        Toasts
+	Scenes        SceneStack
+	Bindings      input.Bindings
+	Cursor        VirtualCursor
+	Profiles      *save.Profiles
+	ActiveProfile string
+	Autosaver     *Autosaver
+	// Time that passed since the previous frame
+	FrameDelta    time.Duration
+	Clock         timestep.Accumulator
+	lastFrame     time.Time
+	pendingClicks uint32
+}
+
+// Small sprites drawn every frame, kept in a single texture
+var atlasImages = []string{
+	"capybara_1.png",
+	"capybara_2.png",
+	"capybara_3.png",
+	"capybara_1_sheet.png",
+	"capybara_2_sheet.png",
+	"capybara_3_sheet.png",
+	"mandarin_orange.png",
+	"mandarin_box_empty.png",
+	"mandarin_box_not_empty.png",
+	"mandarin_box_full.png",
+	"particle_sparkle.png",
+	"particle_drop.png",
 }
 
 func NewGame() Game {
+	err := resources.PackAtlas(atlasImages...)
+	if err != nil {
+		logger.Warning("[Resources] Failed to pack sprites into an atlas: %s", err)
+	}
+
+	animations, err := animation.Parse(resources.Get("animations.json"))
+	if err != nil {
+		logger.Error("[Resources] Failed to load animations, sprites will stay still: %s", err)
+		animations = animation.Library{}
+	}
+
 	audioCtx := audio.NewContext(44000)
 	fnt := resources.GetFont("PixeloidSans-Bold.otf")
 
 	return Game{
 		WorkingDir: ".",
+		Store:      nil,
 		Config:     conf.Default(),
-		Save:       save.Default(),
+		Animations: animations,
+		Sim:        sim.New(save.Default()),
 		AudioPlayers: map[string]*audio.Player{
 			"boop":                    resources.GetAudioPlayer(audioCtx, "boop.wav"),
 			"woop":                    resources.GetAudioPlayer(audioCtx, "woop.wav"),
@@ -76,23 +135,45 @@ func NewGame() Game {
 			DPI:     72,
 			Hinting: font.HintingVertical,
 		}),
-		TouchIDs:            nil,
-		Strokes:             map[*Stroke]struct{}{},
-		PassiveIncomeTicker: 0,
-		MandarinRain:        NewMandarinRain(3, 8),
+		SmallFontFace: util.NewFace(fnt, &opentype.FaceOptions{
+			Size:    20,
+			DPI:     72,
+			Hinting: font.HintingVertical,
+		}),
+		TouchIDs:      nil,
+		Strokes:       map[*Stroke]struct{}{},
+		MandarinRain:  NewMandarinRain(3, 8),
+		Effects:       NewEffects(),
+		FloatingTexts: FloatingTexts{},
+		ClickSpot:     physics.Vec2{},
+		Toasts:        Toasts{},
+		Scenes:        SceneStack{scenes: []Scene{NewMainMenuScene()}},
+		Bindings:      input.Default(),
+		Cursor:        VirtualCursor{},
+		Profiles:      nil,
+		ActiveProfile: "",
+		Autosaver:     nil,
+		FrameDelta:    0,
+		Clock:         timestep.New(StepDuration, maxStepsPerFrame),
+		lastFrame:     time.Time{},
+		pendingClicks: 0,
 	}
 }
 
-// Saves configuration information and game data
-func (g *Game) SaveData(saveFileName string, configurationFileName string) error {
+// Saves configuration information and the active profile's game data
+func (g *Game) SaveData(configurationFileName string) error {
 	// Save configuration information and game data
-	err := save.Create(filepath.Join(g.WorkingDir, saveFileName), g.Save)
+	err := g.SaveProgress()
 	if err != nil {
 		logger.Error("[SaveData] Failed to save game data before closing: %s!", err)
 		return err
 	}
 
-	err = conf.Create(filepath.Join(g.WorkingDir, configurationFileName), g.Config)
+	if g.Store == nil {
+		return nil
+	}
+
+	err = conf.Write(g.Store, configurationFileName, g.Config)
 	if err != nil {
 		logger.Error("[SaveData] Failed to save game configuration before closing: %s!", err)
 		return err
@@ -101,107 +182,159 @@ func (g *Game) SaveData(saveFileName string, configurationFileName string) error
 	return nil
 }
 
+// Starts the world over, used when the save is replaced as a whole
+func (g *Game) ResetWorld() {
+	g.Sim.Reset()
+	g.Clock.Reset()
+	g.pendingClicks = 0
+	g.MandarinRain = NewMandarinRain(3, 8)
+	g.Effects.Clear()
+	g.FloatingTexts.Clear()
+	g.Strokes = map[*Stroke]struct{}{}
+}
+
 func (g *Game) Update() error {
 	if ebiten.IsWindowBeingClosed() {
 		return ebiten.Termination
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
-		// Exit
-		return ebiten.Termination
-	}
-
-	if inpututil.IsKeyJustPressed(ebiten.KeyF12) {
+	if g.Bindings.JustPressed(input.Fullscreen) {
 		g.ToggleFullscreen()
 	}
 
 	g.SaveWindowGeometry()
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
-		// Decrease volume
-		g.DecreaseVolume(0.2)
-	}
+	g.FrameDelta = g.frameDelta()
+	g.Toasts.Update(g.FrameDelta)
+	g.updateAutosave(g.FrameDelta)
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
-		// Increase volume
-		g.IncreaseVolume(0.2)
+	if g.Screen != nil {
+		g.Cursor.Update(g.Screen.Bounds().Dx(), g.Screen.Bounds().Dy(), g.FrameDelta)
 	}
 
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
-		len(inpututil.AppendJustPressedTouchIDs(nil)) != 0 {
-		// Click!
-		g.Save.TimesClicked++
-		g.Save.Points++
-		g.PlaySound("woop")
-	}
+	return g.Scenes.Update(g)
+}
+
+// Returns how much time the current frame covers
+func (g *Game) frameDelta() time.Duration {
+	now := time.Now()
+	last := g.lastFrame
+	g.lastFrame = now
 
-	// Passive points income
-	if g.PassiveIncomeTicker == ebiten.TPS() {
-		g.PassiveIncomeTicker = 0
-		g.Save.Points += g.Save.PassiveIncome
-	} else {
-		g.PassiveIncomeTicker++
+	if tps := ebiten.TPS(); tps > 0 {
+		// Ebiten keeps the tick rate steady on its own
+		return time.Second / time.Duration(tps)
 	}
 
-	if g.Save.Points > 0 && g.Save.Points >= pointsForLevel(g.Save.Level+1) {
-		// Level progression
-		g.Save.Level++
-		g.Save.PassiveIncome++
-		g.PlaySound("levelup")
+	if last.IsZero() || now.Before(last) {
+		return 0
 	}
 
-	// Capybara animation update
-	g.Capybara.Update()
+	return now.Sub(last)
+}
 
-	if !g.MandarinRain.InProgress && g.Save.TimesClicked > 0 && g.Save.TimesClicked%100 == 0 {
-		// Have some oranges!
-		g.MandarinRain.Run(g)
-		logger.Info("Started mandarin rain at %d points!", g.Save.Points)
+// Advances the game world by the time of the current frame in fixed steps.
+// Clicked tells whether the capybara was clicked this frame
+func (g *Game) Tick(clicked bool) {
+	if clicked {
+		// Kept until the next step runs, frames may have none
+		g.pendingClicks++
 	}
 
-	if g.MandarinRain.InProgress {
-		// Calculate mandarin rain logic for this step
-		g.MandarinRain.Update(g)
+	// Releases are only seen on the frame they happen, which may have no steps
+	g.updateStrokes()
+
+	for steps := g.Clock.Advance(g.FrameDelta); steps > 0; steps-- {
+		g.step(StepDuration)
 	}
+}
 
-	if g.MandarinRain.Completed {
-		// Prepare a new mandarin rain
-		g.MandarinRain = NewMandarinRain(3, 8)
+// Advances the game world by a single step of given length
+func (g *Game) step(delta time.Duration) {
+	inputs := sim.Inputs{
+		Delta:         delta,
+		Clicks:        g.pendingClicks,
+		RainDelivered: g.MandarinRain.Delivered,
+		Now:           time.Now(),
 	}
+	g.pendingClicks = 0
 
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && g.MandarinRain.InProgress {
-		physical := g.MandarinRain.PhysicalAt(ebiten.CursorPosition())
-		if physical != nil {
-			s := NewStroke(&MouseStrokeSource{}, physical)
-			g.Strokes[s] = struct{}{}
-		}
+	for _, event := range g.Sim.Step(inputs) {
+		g.handleEvent(event)
 	}
 
-	g.TouchIDs = inpututil.AppendJustPressedTouchIDs(g.TouchIDs[:0])
-	for _, id := range g.TouchIDs {
-		physical := g.MandarinRain.PhysicalAt(ebiten.TouchPosition(id))
-		if physical != nil {
-			s := NewStroke(&TouchStrokeSource{id}, physical)
-			g.Strokes[s] = struct{}{}
-		}
+	// Capybara animation update
+	g.Capybara.Update(g, inputs.Clicks > 0, delta)
+
+	if g.MandarinRain.InProgress {
+		// Calculate mandarin rain logic for this step
+		g.MandarinRain.Update(g, delta)
 	}
 
+	g.Effects.Update(delta)
+	g.FloatingTexts.Update(delta)
+}
+
+// Moves dragged things along with the input and lets go of released ones
+func (g *Game) updateStrokes() {
 	for s := range g.Strokes {
 		s.Update(g)
 		if !s.Physical().Sprite.Dragged {
 			delete(g.Strokes, s)
 		}
 	}
+}
 
-	return nil
+// Plays sounds and shows what the simulation reported
+func (g *Game) handleEvent(event sim.Event) {
+	switch event.Kind {
+	case sim.Clicked:
+		g.PlaySound("woop")
+		g.FloatingTexts.Spawn(g, g.ClickSpot.X, g.ClickSpot.Y, event.Points, clickTextColor)
+
+	case sim.PassiveIncomeEarned:
+		if !event.Points.IsZero() {
+			anchor := g.FloatingTexts.PointsAnchor
+			g.FloatingTexts.Spawn(g, anchor.X, anchor.Y, event.Points, incomeTextColor)
+		}
+
+	case sim.LeveledUp:
+		g.PlaySound("levelup")
+		if g.Screen != nil {
+			g.Effects.Firework(g.Screen.Bounds())
+		}
+		g.Autosave("level up")
+
+	case sim.RainStarted:
+		// Have some oranges!
+		g.MandarinRain.Run(g)
+		logger.Info("Started mandarin rain at %s points!", g.Sim.Save.Points)
+
+	case sim.RainCompleted:
+		// Prepare a new mandarin rain
+		g.PlaySound("mandarin_rain_completed")
+		g.Capybara.Chew(g)
+		g.MandarinRain = NewMandarinRain(3, 8)
+		g.Autosave("mandarin rain completed")
+
+	case sim.AchievementUnlocked:
+		g.Toasts.Push("Achievement unlocked!", event.Achievement.Name)
+		g.PlaySound("mandarin_box_full")
+		logger.Info("[Achievements] Unlocked \"%s\"", event.Achievement.Name)
+	}
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
 	g.Screen = screen
 
-	// Background
 	screen.Fill(color.Black)
+	g.Scenes.Draw(g, screen)
+	g.Toasts.Draw(screen, g)
+}
 
+// Draws background, capybara and mandarin rain
+func (g *Game) DrawWorld(screen *ebiten.Image) {
+	// Background
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Scale(
 		float64(screen.Bounds().Dx())/float64(g.Background.Img.Bounds().Dx()),
@@ -210,63 +343,44 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	screen.DrawImage(g.Background.Img, op)
 
 	// Capybara
-	g.Capybara.Draw(screen, g.Save.Level)
+	g.Capybara.Draw(screen, g.Sim.Save.Level)
 
 	// Mandarin rain
 	if g.MandarinRain.InProgress {
 		g.MandarinRain.Draw(screen)
 	}
 
-	// Points
-	msg := fmt.Sprintf("Points: %d", g.Save.Points)
-	text.Draw(
-		screen,
-		msg,
-		g.FontFace,
-		10,
-		g.FontFace.Metrics().Height.Ceil(),
-		color.White,
-	)
+	// Sparkles, fireworks and juice
+	g.Effects.Draw(screen)
+}
 
-	// Level
-	msg = fmt.Sprintf(
-		"Level: %d (+%d)",
-		g.Save.Level,
-		pointsForLevel(g.Save.Level+1)-g.Save.Points,
-	)
-	text.Draw(
-		screen,
-		msg,
-		g.FontFace,
-		10,
-		g.FontFace.Metrics().Height.Ceil()*2,
-		color.White,
-	)
+// Binds the input to the action and remembers it in configuration
+func (g *Game) Rebind(action input.Action, in input.Input) error {
+	err := g.Bindings.Bind(action, in)
+	if err != nil {
+		return err
+	}
 
-	// Times Clicked
-	msg = fmt.Sprintf("Clicks: %d", g.Save.TimesClicked)
-	text.Draw(
-		screen,
-		msg,
-		g.FontFace,
-		10,
-		screen.Bounds().Dy()-g.FontFace.Metrics().Height.Ceil()*2,
-		color.White,
-	)
+	g.Config.KeyBindings = g.Bindings.ToConfig()
+	return nil
+}
 
-	// Volume
-	msg = fmt.Sprintf("Volume: %d%% (← or →)", int(g.Config.Volume*100.0))
-	text.Draw(
-		screen,
-		msg,
-		g.FontFace,
-		10,
-		screen.Bounds().Dy()-g.FontFace.Metrics().Height.Ceil(),
-		color.White,
-	)
+// Brings back default key bindings
+func (g *Game) ResetBindings() {
+	g.Bindings = input.Default()
+	g.Config.KeyBindings = nil
+}
+
+// Formats the number in the notation chosen in configuration
+func (g *Game) FormatNumber(n bignum.Number) string {
+	return numfmt.Format(n, numfmt.Notation(g.Config.NumberFormat))
+}
+
+// Returns the counter formatted in the notation of choice
+func (g *Game) FormatUint(value uint64) string {
+	return numfmt.FormatUint(value, numfmt.Notation(g.Config.NumberFormat))
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	scaleFactor := ebiten.DeviceScaleFactor()
-	return int(float64(outsideWidth) * scaleFactor), int(float64(outsideHeight) * scaleFactor)
+	return g.Scenes.Layout(outsideWidth, outsideHeight)
 }
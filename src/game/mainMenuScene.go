@@ -0,0 +1,109 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// The first screen shown after the game starts
+type MainMenuScene struct {
+	baseScene
+	menu *Menu
+}
+
+func NewMainMenuScene() *MainMenuScene {
+	return &MainMenuScene{
+		menu: NewMenu(
+			MenuItem{
+				Label: staticLabel("Play"),
+				Action: func(game *Game) error {
+					game.Scenes.Replace(NewClickerScene())
+					return nil
+				},
+			},
+			MenuItem{
+				Label: staticLabel("Profiles"),
+				Action: func(game *Game) error {
+					if game.Profiles == nil {
+						// Progress is not kept, nothing to switch between
+						return nil
+					}
+					game.Scenes.Push(NewProfilesScene())
+					return nil
+				},
+			},
+			MenuItem{
+				Label: staticLabel("Export / import"),
+				Action: func(game *Game) error {
+					game.Scenes.Push(NewTransferScene())
+					return nil
+				},
+			},
+			MenuItem{
+				Label: staticLabel("Settings"),
+				Action: func(game *Game) error {
+					game.Scenes.Push(NewSettingsScene())
+					return nil
+				},
+			},
+			MenuItem{
+				Label: staticLabel("Statistics"),
+				Action: func(game *Game) error {
+					game.Scenes.Push(NewStatisticsScene())
+					return nil
+				},
+			},
+			MenuItem{
+				Label: staticLabel("Quit"),
+				Action: func(game *Game) error {
+					return ebiten.Termination
+				},
+			},
+		),
+	}
+}
+
+func (m *MainMenuScene) Update(game *Game) error {
+	return m.menu.Update(game)
+}
+
+func (m *MainMenuScene) Draw(game *Game, screen *ebiten.Image) {
+	game.DrawWorld(screen)
+
+	text.Draw(
+		screen,
+		"Capyclick",
+		game.FontFace,
+		screen.Bounds().Dx()/10,
+		screen.Bounds().Dy()/6,
+		color.RGBA{255, 165, 0, 255},
+	)
+
+	y := screen.Bounds().Dy()/6 + game.FontFace.Metrics().Height.Ceil()
+	if name := game.ProfileName(); name != "" {
+		text.Draw(screen, "Playing as "+name, game.SmallFontFace, screen.Bounds().Dx()/10, y, color.White)
+		y += game.SmallFontFace.Metrics().Height.Ceil()
+	}
+
+	m.menu.Draw(game, screen, screen.Bounds().Dx()/10, y)
+}
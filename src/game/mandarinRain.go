@@ -19,21 +19,42 @@
 package game
 
 import (
+	"Unbewohnte/capyclick/physics"
+	"Unbewohnte/capyclick/tween"
+	"math"
 	"math/rand"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+const (
+	// Pixels per second squared
+	rainGravity float64 = 300.0
+	// Part of the capybara sprite that is solid, the rest is transparent padding
+	capybaraSolidPart float64 = 0.7
+	// How much the box swells when an orange is put in and when it gets full
+	boxPopOrange float64 = 0.15
+	boxPopFull   float64 = 0.35
+)
+
 type MandarinRain struct {
 	InProgress           bool
 	MandarinBox          *Physical
 	Mandarins            []*Physical
-	Completed            bool
+	Delivered            bool
+	world                *physics.World
+	capybara             *physics.Body
+	boxed                map[*physics.Body]bool
+	boxTouchesCapybara   bool
 	mandarinCount        uint16
 	mandarinInitialCount uint16
 	mandarinsInBox       uint16
 	boxFull              bool
 	mandarinCountRange   [2]uint16
+	// Extra scale of the box while it pops
+	boxPop  float64
+	effects tween.Group
 }
 
 func NewMandarinRain(from uint16, to uint16) *MandarinRain {
@@ -44,15 +65,36 @@ func NewMandarinRain(from uint16, to uint16) *MandarinRain {
 	rain.mandarinCount = rain.mandarinInitialCount
 	rain.mandarinsInBox = 0
 	rain.boxFull = false
-	rain.Completed = false
+	rain.Delivered = false
+	rain.boxed = make(map[*physics.Body]bool)
+	rain.boxTouchesCapybara = false
+	rain.boxPop = 0.0
+	rain.effects = tween.Group{}
+
+	rain.world = physics.NewWorld(physics.V(0.0, rainGravity))
+	rain.world.BoundsFriction = 0.6
+	rain.world.OnContact = rain.onContact
+
+	// The capybara stands still, but everything bounces off it
+	rain.capybara = physics.NewBody(physics.Circle(0.0), 0.0)
+	rain.capybara.Restitution = 0.2
+	rain.capybara.Friction = 0.4
+	rain.world.Add(rain.capybara)
+
+	rain.MandarinBox = NewPhysical(NewSpriteFromFile("mandarin_box_empty.png"), physics.BoxShape, 4.0)
+	rain.MandarinBox.Body.Restitution = 0.3
+	rain.MandarinBox.Body.Friction = 0.8
+	rain.world.Add(rain.MandarinBox.Body)
 
 	rain.Mandarins = make([]*Physical, rain.mandarinInitialCount)
 	for i := 0; i < int(rain.mandarinInitialCount); i++ {
-		rain.Mandarins[i] = NewPhysical(NewSpriteFromFile("mandarin_orange.png"), 10.0)
+		orange := NewPhysical(NewSpriteFromFile("mandarin_orange.png"), physics.CircleShape, 1.0)
+		orange.Body.Restitution = 0.4
+		orange.Body.Friction = 0.5
+		rain.Mandarins[i] = orange
+		rain.world.Add(orange.Body)
 	}
 
-	rain.MandarinBox = NewPhysical(NewSpriteFromFile("mandarin_box_empty.png"), 5.5)
-
 	return &rain
 }
 
@@ -70,6 +112,28 @@ func (mr *MandarinRain) PhysicalAt(x int, y int) *Physical {
 	return nil
 }
 
+// Remembers oranges touching the box and whether the box reached the capybara
+func (mr *MandarinRain) onContact(contact physics.Contact) {
+	box := mr.MandarinBox.Body
+
+	var other *physics.Body
+	switch box {
+	case contact.A:
+		other = contact.B
+	case contact.B:
+		other = contact.A
+	default:
+		return
+	}
+
+	if other == mr.capybara {
+		mr.boxTouchesCapybara = true
+		return
+	}
+
+	mr.boxed[other] = true
+}
+
 func (mr *MandarinRain) Run(game *Game) {
 	if mr.InProgress {
 		return
@@ -78,8 +142,16 @@ func (mr *MandarinRain) Run(game *Game) {
 	mr.InProgress = true
 
 	// Move oranges to random positions on the top of the screen
+	spin := game.Animations["mandarin_spin"]
 	for _, orange := range mr.Mandarins {
 		orange.Sprite.MoveTo(float64(rand.Int31n(int32(game.Screen.Bounds().Dx()-orange.Sprite.Img.Bounds().Dx()))), 10.0, game.Screen)
+		orange.SyncBody()
+
+		// Spin each in its own rhythm
+		orange.Sprite.Play(spin)
+		if spin != nil {
+			orange.Sprite.UpdateAnimation(time.Duration(rand.Int63n(int64(spin.Duration()))))
+		}
 	}
 
 	// Create mandarin box
@@ -87,117 +159,100 @@ func (mr *MandarinRain) Run(game *Game) {
 		float64(rand.Int31n(int32(game.Screen.Bounds().Dx()-mr.MandarinBox.Sprite.Img.Bounds().Dx()))),
 		10.0, game.Screen,
 	)
+	mr.MandarinBox.SyncBody()
 }
 
-func (mr *MandarinRain) Update(game *Game) {
-	// Oranges
-	temp := mr.Mandarins[:0]
-	for _, orange := range mr.Mandarins {
-		orange.Acceleration.Vx = 0.0
-		orange.Acceleration.Vy = 9.81 / orange.Mass
-
-		orange.Velocity.Vx = orange.Velocity.Vx + orange.Acceleration.Vx*0.05
-		orange.Velocity.Vy = orange.Velocity.Vy + orange.Acceleration.Vy*0.05
-
-		oBounds := orange.Sprite.RealBounds()
-		oX := orange.Sprite.X
-		oY := orange.Sprite.Y
-
-		// Constraints
-		// Right
-		if oX+float64(oBounds.Dx()) >= float64(game.Screen.Bounds().Dx()) {
-			orange.Velocity.Vx = -orange.Velocity.Vx * 0.4
-		}
-
-		// Left
-		if oX <= 0 {
-			orange.Velocity.Vx = -orange.Velocity.Vx * 0.4
-		}
-
-		// Up
-		if oY <= 0.0 {
-			orange.Velocity.Vy = -orange.Velocity.Vy * 0.4
-		}
-
-		// Bottom
-		if oY+float64(oBounds.Dy()) >= float64(game.Screen.Bounds().Dy()) {
-			orange.Velocity.Vx = orange.Velocity.Vx * 0.4 // friction on the floor
-			orange.Velocity.Vy = -orange.Velocity.Vy * 0.4
-		}
-
-		orange.Sprite.X += orange.Velocity.Vx
-		orange.Sprite.Y += orange.Velocity.Vy
+// Keeps the body in step with the sprite, dragged sprites lead their bodies
+func (mr *MandarinRain) prepare(physical *Physical, delta time.Duration) {
+	physical.FitCollider()
 
-		// Move the orange
-		orange.Sprite.MoveTo(orange.Sprite.X, orange.Sprite.Y, game.Screen)
-
-		// Check whether it touches mandarin box
-		if orange.InVicinity(mr.MandarinBox.Sprite.X, mr.MandarinBox.Sprite.Y, float64(mr.MandarinBox.Sprite.RealBounds().Dx())) {
-			// Yes!
-			mr.mandarinsInBox++
-			mr.mandarinCount--
-			game.PlaySound("orange_put")
-		} else {
-			// Do not include this orange in the next update (effectively, delete it)
-			temp = append(temp, orange)
-		}
+	if !physical.Sprite.Dragged {
+		// Released bodies keep the velocity they were thrown with
+		physical.Body.Kinematic = false
+		return
 	}
-	mr.Mandarins = temp
 
-	// Orange box
-	mr.MandarinBox.Acceleration.Vx = 0.0
-	mr.MandarinBox.Acceleration.Vy = 9.81 / mr.MandarinBox.Mass
+	center := physical.spriteCenter()
+	if physical.Body.Kinematic && delta > 0 {
+		physical.Body.Velocity = center.Sub(physical.Body.Position).Scale(1.0 / delta.Seconds())
+	} else {
+		physical.Body.Velocity = physics.Vec2{}
+	}
+	physical.Body.Kinematic = true
+	physical.Body.Position = center
+}
 
-	mr.MandarinBox.Velocity.Vx = mr.MandarinBox.Velocity.Vx + mr.MandarinBox.Acceleration.Vx*0.05
-	mr.MandarinBox.Velocity.Vy = mr.MandarinBox.Velocity.Vy + mr.MandarinBox.Acceleration.Vy*0.05
+// Swells the box by strength and bounces it back
+func (mr *MandarinRain) popBox(strength float64) {
+	mr.effects.Clear()
+	mr.effects.Add(tween.Sequence(
+		tween.To(&mr.boxPop, strength, time.Millisecond*80, tween.OutQuad),
+		tween.To(&mr.boxPop, 0.0, time.Millisecond*400, tween.OutBounce),
+	))
+}
 
-	mBounds := mr.MandarinBox.Sprite.RealBounds()
-	mX := mr.MandarinBox.Sprite.X
-	mY := mr.MandarinBox.Sprite.Y
+// Moves the rain on by delta
+func (mr *MandarinRain) Update(game *Game, delta time.Duration) {
+	screen := game.Screen.Bounds()
+	mr.world.Bounds = physics.RectAt(0.0, 0.0, float64(screen.Dx()), float64(screen.Dy()))
 
-	// Right
-	if mX+float64(mBounds.Dx()) >= float64(game.Screen.Bounds().Dx()) {
-		mr.MandarinBox.Velocity.Vx = -mr.MandarinBox.Velocity.Vx * 0.3
-	}
+	// The capybara
+	capybaraBounds := game.Capybara.Sprite.RealBounds()
+	mr.capybara.Position = physics.V(
+		game.Capybara.Sprite.X+float64(capybaraBounds.Dx())/2.0,
+		game.Capybara.Sprite.Y+float64(capybaraBounds.Dy())/2.0,
+	)
+	mr.capybara.Collider = physics.Circle(
+		math.Min(float64(capybaraBounds.Dx()), float64(capybaraBounds.Dy())) / 2.0 * capybaraSolidPart,
+	)
 
-	// Left
-	if mX <= 0 {
-		mr.MandarinBox.Velocity.Vx = -mr.MandarinBox.Velocity.Vx * 0.3
+	mr.prepare(mr.MandarinBox, delta)
+	for _, orange := range mr.Mandarins {
+		mr.prepare(orange, delta)
 	}
 
-	// Up
-	if mY <= 0.0 {
-		mr.MandarinBox.Velocity.Vy = -mr.MandarinBox.Velocity.Vy * 0.3
-	}
+	mr.boxTouchesCapybara = false
+	mr.world.Advance(delta)
 
-	// Bottom
-	if mY+float64(mBounds.Dy()) >= float64(game.Screen.Bounds().Dy()) {
-		mr.MandarinBox.Velocity.Vx = mr.MandarinBox.Velocity.Vx * 0.3 // friction on the floor
-		mr.MandarinBox.Velocity.Vy = -mr.MandarinBox.Velocity.Vy * 0.3
+	if !mr.MandarinBox.Sprite.Dragged {
+		mr.MandarinBox.SyncSprite()
 	}
 
-	mr.MandarinBox.Sprite.X += mr.MandarinBox.Velocity.Vx
-	mr.MandarinBox.Sprite.Y += mr.MandarinBox.Velocity.Vy
+	// Oranges
+	temp := mr.Mandarins[:0]
+	for _, orange := range mr.Mandarins {
+		orange.Sprite.UpdateAnimation(delta)
+		if !mr.boxed[orange.Body] {
+			if !orange.Sprite.Dragged {
+				orange.SyncSprite()
+			}
+			temp = append(temp, orange)
+			continue
+		}
 
-	// Move box
-	mr.MandarinBox.Sprite.MoveTo(mr.MandarinBox.Sprite.X, mr.MandarinBox.Sprite.Y, game.Screen)
+		// Into the box it goes! Do not include this orange in the next update (effectively, delete it)
+		orange.Sprite.Dragged = false
+		mr.world.Remove(orange.Body)
+		mr.mandarinsInBox++
+		mr.mandarinCount--
+		game.PlaySound("orange_put")
+		game.Effects.Splash(orange.Body.Position.X, orange.Body.Position.Y)
+		mr.popBox(boxPopOrange)
+	}
+	mr.Mandarins = temp
+	mr.boxed = make(map[*physics.Body]bool)
 
 	if mr.mandarinsInBox == mr.mandarinInitialCount && !mr.boxFull {
 		// All oranges are in a box!
 		mr.boxFull = true
 		game.PlaySound("mandarin_box_full")
+		mr.popBox(boxPopFull)
 	}
+	mr.effects.Update(delta)
 
-	// If the box is full with mandarines and is near capybara - end mandarin rain and reward with points!
-	if mr.boxFull && mr.MandarinBox.InVicinity(
-		game.Capybara.Sprite.X+float64(game.Capybara.Sprite.RealBounds().Dx()/2),
-		game.Capybara.Sprite.Y+float64(game.Capybara.Sprite.RealBounds().Dy()/2),
-		float64(game.Screen.Bounds().Dx())/7) {
-		// Give a reward and finish this mandarin rain!
-		game.Save.Points += pointsForLevel(game.Save.Level+1) / 5
-		game.PlaySound("mandarin_rain_completed")
-		mr.InProgress = false
-		mr.Completed = true
+	// If the box is full with mandarines and touches the capybara - it is delivered, the reward is up to the simulation
+	if mr.boxFull && !mr.Delivered && mr.boxTouchesCapybara {
+		mr.Delivered = true
 	}
 }
 
@@ -215,13 +270,21 @@ func (mr *MandarinRain) Draw(screen *ebiten.Image) {
 		op := &ebiten.DrawImageOptions{}
 		scale := float64(screen.Bounds().Dx()) / float64(mr.MandarinBox.Sprite.Img.Bounds().Dx()) / 6.0
 		mr.MandarinBox.Sprite.Scale = scale // Save current scale for proper collision detection
-		op.GeoM.Scale(scale, scale)
-		op.GeoM.Translate(mr.MandarinBox.Sprite.X, mr.MandarinBox.Sprite.Y)
+		// Pops around the center, the collider keeps its size
+		boxWidth := float64(mr.MandarinBox.Sprite.Img.Bounds().Dx())
+		boxHeight := float64(mr.MandarinBox.Sprite.Img.Bounds().Dy())
+		op.GeoM.Translate(-boxWidth/2.0, -boxHeight/2.0)
+		op.GeoM.Scale(scale*(1.0+mr.boxPop), scale*(1.0+mr.boxPop))
+		op.GeoM.Translate(
+			mr.MandarinBox.Sprite.X+boxWidth*scale/2.0,
+			mr.MandarinBox.Sprite.Y+boxHeight*scale/2.0,
+		)
 		screen.DrawImage(mr.MandarinBox.Sprite.Img, op)
 
 		// Oranges
 		for _, orange := range mr.Mandarins {
 			op = &ebiten.DrawImageOptions{}
+			orange.Sprite.applyFrame(op)
 			scale = float64(screen.Bounds().Dx()) / float64(orange.Sprite.Img.Bounds().Dx()) / 11.5
 			orange.Sprite.Scale = scale // Save current scale for proper collision detection
 			op.GeoM.Scale(scale, scale)
@@ -0,0 +1,178 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/input"
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Returns a panel for full-screen menus with some margin around it
+func menuPanel(screen *ebiten.Image) image.Rectangle {
+	margin := screen.Bounds().Dx() / 16
+	return image.Rect(margin, margin, screen.Bounds().Dx()-margin, screen.Bounds().Dy()-margin)
+}
+
+// Draws a dark translucent panel menus and dialogs are put on
+func drawPanel(screen *ebiten.Image, panel image.Rectangle) {
+	vector.DrawFilledRect(
+		screen,
+		float32(panel.Min.X),
+		float32(panel.Min.Y),
+		float32(panel.Dx()),
+		float32(panel.Dy()),
+		color.RGBA{20, 12, 4, 220},
+		false,
+	)
+}
+
+type MenuItem struct {
+	// Returns text to show, called every frame so it may change
+	Label func(game *Game) string
+	// Called when the item is chosen
+	Action func(game *Game) error
+	// Called with -1 or +1 when the item is changed with left/right (optional)
+	Adjust func(game *Game, direction int)
+}
+
+// Returns a label that never changes
+func staticLabel(label string) func(game *Game) string {
+	return func(game *Game) string {
+		return label
+	}
+}
+
+// Vertical list of items navigated with keyboard, mouse or touch.
+// Scrolls when there are more items than fit on the screen
+type Menu struct {
+	Items    []MenuItem
+	Selected int
+	// Font to draw items with, game's main font if nil
+	Face   font.Face
+	rows   []image.Rectangle
+	offset int
+}
+
+func NewMenu(items ...MenuItem) *Menu {
+	return &Menu{
+		Items:    items,
+		Selected: 0,
+		rows:     make([]image.Rectangle, len(items)),
+	}
+}
+
+func (m *Menu) activate(game *Game, index int) error {
+	if index < 0 || index >= len(m.Items) {
+		return nil
+	}
+
+	m.Selected = index
+	game.PlaySound("boop")
+	if m.Items[index].Action == nil {
+		return nil
+	}
+
+	return m.Items[index].Action(game)
+}
+
+func (m *Menu) Update(game *Game) error {
+	if game.Bindings.JustPressed(input.Up) {
+		m.Selected = (m.Selected - 1 + len(m.Items)) % len(m.Items)
+	}
+
+	if game.Bindings.JustPressed(input.Down) {
+		m.Selected = (m.Selected + 1) % len(m.Items)
+	}
+
+	if adjust := m.Items[m.Selected].Adjust; adjust != nil {
+		if game.Bindings.JustPressed(input.Left) {
+			adjust(game, -1)
+		}
+
+		if game.Bindings.JustPressed(input.Right) {
+			adjust(game, 1)
+		}
+	}
+
+	if game.Bindings.JustPressed(input.Confirm) {
+		return m.activate(game, m.Selected)
+	}
+
+	for _, point := range justPressedPoints(game.Bindings) {
+		for index, row := range m.rows {
+			if point.In(row) {
+				return m.activate(game, index)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Draws menu items one under another starting at given coordinates
+func (m *Menu) Draw(game *Game, screen *ebiten.Image, x int, y int) {
+	face := m.Face
+	if face == nil {
+		face = game.FontFace
+	}
+	lineHeight := face.Metrics().Height.Ceil() + 10
+
+	// Keep the selected item on screen
+	visible := (screen.Bounds().Dy() - x - y) / lineHeight
+	if visible < 1 {
+		visible = 1
+	}
+	if m.Selected < m.offset {
+		m.offset = m.Selected
+	} else if m.Selected >= m.offset+visible {
+		m.offset = m.Selected - visible + 1
+	}
+	if m.offset > len(m.Items)-visible {
+		m.offset = len(m.Items) - visible
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+
+	for index, item := range m.Items {
+		row := index - m.offset
+		if row < 0 || row >= visible {
+			// Scrolled out, can't be clicked
+			m.rows[index] = image.Rectangle{}
+			continue
+		}
+
+		label := item.Label(game)
+		var clr color.Color = color.White
+		if index == m.Selected {
+			label = "> " + label
+			clr = color.RGBA{255, 165, 0, 255}
+		}
+
+		m.rows[index] = image.Rect(x, y+lineHeight*row, screen.Bounds().Dx()-x, y+lineHeight*(row+1))
+		text.Draw(screen, label, face, x, y+lineHeight*(row+1)-10, clr)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/input"
+	"Unbewohnte/capyclick/logger"
+	"Unbewohnte/capyclick/sim"
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Rewards points earned while the game was closed and shows a summary if there is any
+func (g *Game) ApplyOfflineProgress(now time.Time) {
+	report := sim.OfflineEarnings(g.Sim.Save, g.Config, now)
+	if report.Points.IsZero() {
+		return
+	}
+
+	g.Sim.Save.Earn(report.Points)
+	g.Scenes.Push(&WelcomeBackScene{Report: report})
+	logger.Info("[Offline] Earned %s points while away for %s", report.Points, report.Away)
+}
+
+// Summary of the offline earnings shown on startup
+type WelcomeBackScene struct {
+	overlayScene
+	Report sim.OfflineReport
+}
+
+func (w *WelcomeBackScene) Update(game *Game) error {
+	if len(justPressedPoints(game.Bindings)) != 0 ||
+		game.Bindings.JustPressed(input.Confirm) ||
+		game.Bindings.JustPressed(input.Back) {
+		game.Scenes.Pop()
+		game.PlaySound("boop")
+	}
+
+	return nil
+}
+
+func (w *WelcomeBackScene) Draw(game *Game, screen *ebiten.Image) {
+	margin := screen.Bounds().Dx() / 10
+	lineHeight := game.SmallFontFace.Metrics().Height.Ceil()
+	height := game.FontFace.Metrics().Height.Ceil() + lineHeight*5
+
+	x := margin
+	y := screen.Bounds().Dy()/2 - height/2
+
+	vector.DrawFilledRect(
+		screen,
+		float32(x),
+		float32(y),
+		float32(screen.Bounds().Dx()-margin*2),
+		float32(height),
+		color.RGBA{20, 12, 4, 230},
+		false,
+	)
+
+	y += game.FontFace.Metrics().Height.Ceil()
+	text.Draw(screen, "Welcome back!", game.FontFace, x+10, y, color.White)
+
+	y += lineHeight
+	text.Draw(
+		screen,
+		fmt.Sprintf("You were away for %s", w.Report.Away.Round(time.Second)),
+		game.SmallFontFace,
+		x+10, y, color.White,
+	)
+
+	if w.Report.Counted < w.Report.Away {
+		y += lineHeight
+		text.Draw(
+			screen,
+			fmt.Sprintf("(only %s counted)", w.Report.Counted.Round(time.Second)),
+			game.SmallFontFace,
+			x+10, y, color.Gray{Y: 160},
+		)
+	}
+
+	y += lineHeight
+	text.Draw(
+		screen,
+		fmt.Sprintf("Capybara earned %s points", game.FormatNumber(w.Report.Points)),
+		game.SmallFontFace,
+		x+10, y, color.White,
+	)
+
+	y += lineHeight * 2
+	text.Draw(screen, "Click to continue", game.SmallFontFace, x+10, y, color.Gray{Y: 160})
+}
@@ -0,0 +1,94 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/input"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Stops the clicker and offers to leave it
+type PauseScene struct {
+	overlayScene
+	menu *Menu
+}
+
+func NewPauseScene() *PauseScene {
+	return &PauseScene{
+		menu: NewMenu(
+			MenuItem{
+				Label: staticLabel("Resume"),
+				Action: func(game *Game) error {
+					game.Scenes.Pop()
+					return nil
+				},
+			},
+			MenuItem{
+				Label: staticLabel("Settings"),
+				Action: func(game *Game) error {
+					game.Scenes.Push(NewSettingsScene())
+					return nil
+				},
+			},
+			MenuItem{
+				Label: staticLabel("Statistics"),
+				Action: func(game *Game) error {
+					game.Scenes.Push(NewStatisticsScene())
+					return nil
+				},
+			},
+			MenuItem{
+				Label: staticLabel("Main menu"),
+				Action: func(game *Game) error {
+					game.Scenes.Reset(NewMainMenuScene())
+					return nil
+				},
+			},
+			MenuItem{
+				Label: staticLabel("Quit"),
+				Action: func(game *Game) error {
+					return ebiten.Termination
+				},
+			},
+		),
+	}
+}
+
+func (p *PauseScene) Update(game *Game) error {
+	if game.Bindings.JustPressed(input.Back) || game.Bindings.JustPressed(input.Pause) {
+		// Resume
+		game.Scenes.Pop()
+		game.PlaySound("boop")
+		return nil
+	}
+
+	return p.menu.Update(game)
+}
+
+func (p *PauseScene) Draw(game *Game, screen *ebiten.Image) {
+	drawPanel(screen, screen.Bounds())
+
+	x := screen.Bounds().Dx() / 10
+	y := screen.Bounds().Dy() / 6
+	text.Draw(screen, "Paused", game.FontFace, x, y, color.White)
+	p.menu.Draw(game, screen, x, y+game.FontFace.Metrics().Height.Ceil())
+}
@@ -18,41 +18,59 @@
 
 package game
 
-import "math"
+import (
+	"Unbewohnte/capyclick/physics"
+	"math"
+)
 
-type Vec2f struct {
-	Vx float64
-	Vy float64
+// Sprite moved around by a physics body
+type Physical struct {
+	Sprite *Sprite
+	Body   *physics.Body
 }
 
-func newVec2f(x float64, y float64) Vec2f {
-	return Vec2f{
-		Vx: x,
-		Vy: y,
+// Returns a sprite driven by a body of given shape and mass.
+// The collider follows the size the sprite is drawn with
+func NewPhysical(sprite *Sprite, shape physics.Shape, mass float64) *Physical {
+	physical := &Physical{
+		Sprite: sprite,
+		Body:   physics.NewBody(physics.Collider{Shape: shape}, mass),
 	}
-}
+	physical.FitCollider()
 
-type Physical struct {
-	Sprite       *Sprite
-	Velocity     Vec2f
-	Acceleration Vec2f
-	Mass         float64
+	return physical
 }
 
-func NewPhysical(sprite *Sprite, mass float64) *Physical {
-	return &Physical{
-		Sprite:       sprite,
-		Velocity:     newVec2f(0.0, 0.0),
-		Acceleration: newVec2f(0.0, 0.0),
-		Mass:         10.0,
+// Resizes the collider to the sprite's current size
+func (ph *Physical) FitCollider() {
+	bounds := ph.Sprite.RealBounds()
+	width := float64(bounds.Dx())
+	height := float64(bounds.Dy())
+
+	if ph.Body.Collider.Shape == physics.CircleShape {
+		ph.Body.Collider = physics.Circle(math.Min(width, height) / 2.0)
+	} else {
+		ph.Body.Collider = physics.Box(width, height)
 	}
 }
 
-// Returns true if x and y coordinates are in the radius of the physical sprite
-func (ph *Physical) InVicinity(x float64, y float64, radius float64) bool {
-	distance := math.Sqrt(
-		math.Pow(ph.Sprite.X-x, 2.0) + math.Pow(ph.Sprite.Y-y, 2.0),
+// Returns the center of the sprite
+func (ph *Physical) spriteCenter() physics.Vec2 {
+	bounds := ph.Sprite.RealBounds()
+	return physics.V(
+		ph.Sprite.X+float64(bounds.Dx())/2.0,
+		ph.Sprite.Y+float64(bounds.Dy())/2.0,
 	)
+}
+
+// Puts the body where the sprite is
+func (ph *Physical) SyncBody() {
+	ph.Body.Position = ph.spriteCenter()
+}
 
-	return distance <= radius
+// Puts the sprite where the body is
+func (ph *Physical) SyncSprite() {
+	bounds := ph.Sprite.RealBounds()
+	ph.Sprite.X = ph.Body.Position.X - float64(bounds.Dx())/2.0
+	ph.Sprite.Y = ph.Body.Position.Y - float64(bounds.Dy())/2.0
 }
@@ -0,0 +1,125 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/input"
+	"Unbewohnte/capyclick/logger"
+	"Unbewohnte/capyclick/prestige"
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Asks the player to confirm a rebirth
+type PrestigeScene struct {
+	overlayScene
+	yesButton image.Rectangle
+	noButton  image.Rectangle
+}
+
+func NewPrestigeScene() *PrestigeScene {
+	return &PrestigeScene{}
+}
+
+func (p *PrestigeScene) confirm(game *Game) {
+	game.Scenes.Pop()
+
+	gained, err := prestige.Reset(&game.Sim.Save)
+	if err != nil {
+		return
+	}
+
+	// Start the new run from a clean state
+	game.ResetWorld()
+	game.Autosave("rebirth")
+
+	game.PlaySound("mandarin_rain_completed")
+	logger.Info(
+		"[Prestige] Rebirth #%d, gained %s golden mandarins (x%.1f)",
+		game.Sim.Save.Prestiges, gained, prestige.Multiplier(game.Sim.Save),
+	)
+}
+
+func (p *PrestigeScene) Update(game *Game) error {
+	pressed := justPressedPoints(game.Bindings)
+	if game.Bindings.JustPressed(input.Confirm) ||
+		anyPointIn(pressed, p.yesButton) {
+		p.confirm(game)
+		return nil
+	}
+
+	if game.Bindings.JustPressed(input.Back) ||
+		anyPointIn(pressed, p.noButton) {
+		game.Scenes.Pop()
+		return nil
+	}
+
+	return nil
+}
+
+func (p *PrestigeScene) Draw(game *Game, screen *ebiten.Image) {
+	margin := screen.Bounds().Dx() / 10
+	lineHeight := game.SmallFontFace.Metrics().Height.Ceil()
+	height := game.FontFace.Metrics().Height.Ceil()*2 + lineHeight*6
+
+	x := margin
+	y := screen.Bounds().Dy()/2 - height/2
+	width := screen.Bounds().Dx() - margin*2
+
+	vector.DrawFilledRect(
+		screen,
+		float32(x),
+		float32(y),
+		float32(width),
+		float32(height),
+		color.RGBA{20, 12, 4, 230},
+		false,
+	)
+
+	y += game.FontFace.Metrics().Height.Ceil()
+	text.Draw(screen, "Rebirth?", game.FontFace, x+10, y, color.White)
+
+	available := prestige.Available(game.Sim.Save)
+	lines := []string{
+		"Points, level and passive income",
+		"will be reset.",
+		fmt.Sprintf("You will get %s golden mandarins", game.FormatNumber(available)),
+		fmt.Sprintf(
+			"Multiplier: x%.1f -> x%.1f",
+			prestige.Multiplier(game.Sim.Save),
+			prestige.MultiplierFor(game.Sim.Save.GoldenMandarins.Add(available)),
+		),
+	}
+	for _, line := range lines {
+		y += lineHeight
+		text.Draw(screen, line, game.SmallFontFace, x+10, y, color.White)
+	}
+
+	// Buttons
+	y += lineHeight + game.FontFace.Metrics().Height.Ceil()
+	p.yesButton = image.Rect(x, y-game.FontFace.Metrics().Height.Ceil(), x+width/2, y+lineHeight/2)
+	p.noButton = image.Rect(x+width/2, p.yesButton.Min.Y, x+width, p.yesButton.Max.Y)
+	text.Draw(screen, fmt.Sprintf("Yes (%s)", game.Bindings.Label(input.Confirm)), game.FontFace, p.yesButton.Min.X+10, y, color.RGBA{255, 165, 0, 255})
+	text.Draw(screen, fmt.Sprintf("No (%s)", game.Bindings.Label(input.Back)), game.FontFace, p.noButton.Min.X+10, y, color.White)
+}
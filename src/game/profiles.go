@@ -0,0 +1,116 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/logger"
+	"Unbewohnte/capyclick/save"
+	"errors"
+	"time"
+)
+
+// Writes the game data into the active profile, does nothing without one
+func (g *Game) SaveProgress() error {
+	if g.Profiles == nil || g.ActiveProfile == "" {
+		return nil
+	}
+
+	g.Sim.Save.LastSavedUnix = uint64(time.Now().Unix())
+	if g.Autosaver == nil {
+		return g.Profiles.Store(g.ActiveProfile, g.Sim.Save)
+	}
+
+	// Go through the autosaver so that an older snapshot never lands after this one
+	done := make(chan error, 1)
+	g.Autosaver.request(autosaveRequest{
+		profile: g.ActiveProfile,
+		save:    g.Sim.Save.Clone(),
+		reason:  "manual",
+		done:    done,
+	})
+	return <-done
+}
+
+// Saves the active profile, loads the chosen one and goes to the main menu
+func (g *Game) SwitchProfile(id string) error {
+	if g.Profiles == nil {
+		return save.ErrUnknownSlot
+	}
+
+	if id == g.ActiveProfile {
+		g.Scenes.Reset(NewMainMenuScene())
+		return nil
+	}
+
+	loaded, err := g.Profiles.Load(id)
+	var tamperedErr *save.TamperedError
+	if errors.As(err, &tamperedErr) {
+		logger.Warning("[Profiles] %s", err)
+		handled := save.HandleTampered(*loaded, save.TamperPolicy(g.Config.TamperPolicy))
+		loaded = &handled
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Keep progress of the profile being left
+	err = g.SaveProgress()
+	if err != nil {
+		return err
+	}
+
+	err = g.Profiles.Switch(id)
+	if err != nil {
+		return err
+	}
+
+	g.ActiveProfile = id
+	g.Sim.Save = *loaded
+	g.ResetWorld()
+	g.Scenes.Reset(NewMainMenuScene())
+
+	// Reward the time spent away before marking the save as opened
+	g.ApplyOfflineProgress(time.Now())
+	g.Sim.Save.LastOpenedUnix = uint64(time.Now().Unix())
+
+	slot, _ := g.Profiles.Get(id)
+	logger.Info("[Profiles] Playing as \"%s\"", slot.Name)
+
+	return nil
+}
+
+// Returns the name of the active profile or an empty string
+func (g *Game) ProfileName() string {
+	if g.Profiles == nil {
+		return ""
+	}
+
+	slot, ok := g.Profiles.Get(g.ActiveProfile)
+	if !ok {
+		return ""
+	}
+
+	return slot.Name
+}
+
+// Shows the profile picker instead of everything else
+func (g *Game) PickProfile() {
+	g.Scenes.Reset(NewProfilesScene())
+}
@@ -0,0 +1,365 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/input"
+	"Unbewohnte/capyclick/save"
+	"fmt"
+	"image/color"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Returns a one-line summary of the slot
+func slotSummary(game *Game, slot save.Slot) string {
+	lastPlayed := "never"
+	if slot.LastPlayedUnix != 0 {
+		lastPlayed = time.Unix(int64(slot.LastPlayedUnix), 0).Format("2006-01-02")
+	}
+
+	return fmt.Sprintf(
+		"level %s, %s played, last %s",
+		game.FormatUint(uint64(slot.Level)),
+		time.Duration(slot.PlaytimeSeconds)*time.Second,
+		lastPlayed,
+	)
+}
+
+// Lists profiles to pick one, shown at startup
+type ProfilesScene struct {
+	baseScene
+	menu *Menu
+}
+
+func NewProfilesScene() *ProfilesScene {
+	return &ProfilesScene{}
+}
+
+// Rebuilds the menu when profiles are added or removed
+func (p *ProfilesScene) rebuild(game *Game) {
+	slots := game.Profiles.List()
+	if p.menu != nil && len(p.menu.Items) == len(slots)+2 {
+		return
+	}
+
+	var items []MenuItem
+	selected := 0
+	for index, slot := range slots {
+		id := slot.ID
+		if id == game.Profiles.Current {
+			selected = index
+		}
+
+		items = append(items, MenuItem{
+			Label: func(game *Game) string {
+				slot, _ := game.Profiles.Get(id)
+				if id == game.ActiveProfile {
+					return slot.Name + " (playing)"
+				}
+				return slot.Name
+			},
+			Action: func(game *Game) error {
+				game.Scenes.Push(NewProfileScene(id))
+				return nil
+			},
+		})
+	}
+
+	items = append(items, MenuItem{
+		Label: staticLabel("New profile"),
+		Action: func(game *Game) error {
+			game.Scenes.Push(NewNameInputScene(
+				"New profile",
+				fmt.Sprintf("Player %d", game.Profiles.NextID),
+				func(game *Game, name string) error {
+					_, err := game.Profiles.Create(name, save.Default())
+					return err
+				},
+			))
+			return nil
+		},
+	})
+
+	if game.ActiveProfile != "" {
+		items = append(items, MenuItem{
+			Label: staticLabel("Back"),
+			Action: func(game *Game) error {
+				game.Scenes.Pop()
+				return nil
+			},
+		})
+	} else {
+		items = append(items, MenuItem{
+			Label: staticLabel("Quit"),
+			Action: func(game *Game) error {
+				return ebiten.Termination
+			},
+		})
+	}
+
+	if p.menu != nil {
+		selected = p.menu.Selected
+		if selected >= len(items) {
+			selected = len(items) - 1
+		}
+	}
+	p.menu = NewMenu(items...)
+	p.menu.Selected = selected
+}
+
+func (p *ProfilesScene) Update(game *Game) error {
+	if game.Profiles == nil {
+		// Nowhere to keep profiles
+		game.Scenes.Pop()
+		return nil
+	}
+	p.rebuild(game)
+
+	if game.ActiveProfile != "" && game.Bindings.JustPressed(input.Back) {
+		game.Scenes.Pop()
+		game.PlaySound("boop")
+		return nil
+	}
+
+	return p.menu.Update(game)
+}
+
+func (p *ProfilesScene) Draw(game *Game, screen *ebiten.Image) {
+	game.DrawWorld(screen)
+
+	panel := menuPanel(screen)
+	drawPanel(screen, panel)
+
+	x := panel.Min.X + 20
+	y := panel.Min.Y + game.FontFace.Metrics().Height.Ceil()
+	text.Draw(screen, "Profiles", game.FontFace, x, y, color.White)
+
+	if p.menu == nil {
+		return
+	}
+
+	y += game.SmallFontFace.Metrics().Height.Ceil()
+	if slots := game.Profiles.List(); p.menu.Selected < len(slots) {
+		text.Draw(screen, slotSummary(game, slots[p.menu.Selected]), game.SmallFontFace, x, y, color.Gray{Y: 160})
+	} else {
+		text.Draw(screen, "Who is playing?", game.SmallFontFace, x, y, color.Gray{Y: 160})
+	}
+
+	p.menu.Draw(game, screen, x, y+game.SmallFontFace.Metrics().Height.Ceil()/2)
+}
+
+// Things to do with a single profile
+type ProfileScene struct {
+	baseScene
+	id            string
+	menu          *Menu
+	confirmDelete bool
+	message       string
+}
+
+func NewProfileScene(id string) *ProfileScene {
+	scene := &ProfileScene{id: id}
+	scene.menu = NewMenu(
+		MenuItem{
+			Label: staticLabel("Play"),
+			Action: func(game *Game) error {
+				err := game.SwitchProfile(id)
+				if err != nil {
+					scene.message = err.Error()
+				}
+				return nil
+			},
+		},
+		MenuItem{
+			Label: staticLabel("Rename"),
+			Action: func(game *Game) error {
+				slot, _ := game.Profiles.Get(id)
+				game.Scenes.Push(NewNameInputScene(
+					"Rename profile",
+					slot.Name,
+					func(game *Game, name string) error {
+						return game.Profiles.Rename(id, name)
+					},
+				))
+				return nil
+			},
+		},
+		MenuItem{
+			Label: func(game *Game) string {
+				if scene.confirmDelete {
+					return "Delete (choose again to confirm)"
+				}
+				return "Delete"
+			},
+			Action: func(game *Game) error {
+				if id == game.ActiveProfile {
+					scene.message = "Switch to another profile to delete this one"
+					return nil
+				}
+
+				if !scene.confirmDelete {
+					scene.confirmDelete = true
+					return nil
+				}
+
+				err := game.Profiles.Delete(id)
+				if err != nil {
+					scene.message = err.Error()
+					return nil
+				}
+				game.Scenes.Pop()
+				return nil
+			},
+		},
+		MenuItem{
+			Label: staticLabel("Back"),
+			Action: func(game *Game) error {
+				game.Scenes.Pop()
+				return nil
+			},
+		},
+	)
+
+	return scene
+}
+
+func (p *ProfileScene) Update(game *Game) error {
+	if game.Bindings.JustPressed(input.Back) {
+		game.Scenes.Pop()
+		game.PlaySound("boop")
+		return nil
+	}
+
+	if p.menu.Selected != 2 {
+		// Moving away from deletion cancels it
+		p.confirmDelete = false
+	}
+
+	return p.menu.Update(game)
+}
+
+func (p *ProfileScene) Draw(game *Game, screen *ebiten.Image) {
+	game.DrawWorld(screen)
+
+	panel := menuPanel(screen)
+	drawPanel(screen, panel)
+
+	slot, _ := game.Profiles.Get(p.id)
+
+	x := panel.Min.X + 20
+	y := panel.Min.Y + game.FontFace.Metrics().Height.Ceil()
+	text.Draw(screen, slot.Name, game.FontFace, x, y, color.White)
+
+	lineHeight := game.SmallFontFace.Metrics().Height.Ceil()
+	y += lineHeight
+	text.Draw(screen, slotSummary(game, slot), game.SmallFontFace, x, y, color.Gray{Y: 160})
+
+	if p.message != "" {
+		y += lineHeight
+		text.Draw(screen, p.message, game.SmallFontFace, x, y, color.RGBA{255, 80, 80, 255})
+	}
+
+	p.menu.Draw(game, screen, x, y+lineHeight/2)
+}
+
+// Asks for a line of text such as a profile name
+type NameInputScene struct {
+	overlayScene
+	Title  string
+	Name   string
+	OnDone func(game *Game, name string) error
+	err    error
+}
+
+func NewNameInputScene(title string, name string, onDone func(game *Game, name string) error) *NameInputScene {
+	return &NameInputScene{
+		Title:  title,
+		Name:   name,
+		OnDone: onDone,
+	}
+}
+
+func (n *NameInputScene) Update(game *Game) error {
+	for _, char := range ebiten.AppendInputChars(nil) {
+		if unicode.IsPrint(char) && utf8.RuneCountInString(n.Name) < save.MaxProfileNameLength {
+			n.Name += string(char)
+		}
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(n.Name) > 0 {
+		_, size := utf8.DecodeLastRuneInString(n.Name)
+		n.Name = n.Name[:len(n.Name)-size]
+	}
+
+	// Keys that type text can't accept or cancel it, gamepad buttons can
+	confirmed := inpututil.IsKeyJustPressed(ebiten.KeyEnter)
+	if in, ok := game.Bindings.JustPressedInput(input.Confirm); ok && in.Device() != input.DeviceKey {
+		confirmed = true
+	}
+
+	cancelled := inpututil.IsKeyJustPressed(ebiten.KeyEscape)
+	if in, ok := game.Bindings.JustPressedInput(input.Back); ok && in.Device() != input.DeviceKey {
+		cancelled = true
+	}
+
+	if cancelled {
+		game.Scenes.Pop()
+		game.PlaySound("boop")
+		return nil
+	}
+
+	if confirmed {
+		game.PlaySound("boop")
+		n.err = n.OnDone(game, n.Name)
+		if n.err == nil {
+			game.Scenes.Pop()
+		}
+	}
+
+	return nil
+}
+
+func (n *NameInputScene) Draw(game *Game, screen *ebiten.Image) {
+	lineHeight := game.SmallFontFace.Metrics().Height.Ceil()
+	panel := menuPanel(screen)
+	panel.Min.Y = screen.Bounds().Dy()/2 - lineHeight*4
+	panel.Max.Y = screen.Bounds().Dy()/2 + lineHeight*4
+	drawPanel(screen, panel)
+
+	x := panel.Min.X + 20
+	y := panel.Min.Y + game.FontFace.Metrics().Height.Ceil()
+	text.Draw(screen, n.Title, game.FontFace, x, y, color.White)
+
+	y += game.FontFace.Metrics().Height.Ceil()
+	text.Draw(screen, n.Name+"_", game.FontFace, x, y, color.RGBA{255, 165, 0, 255})
+
+	y += lineHeight * 2
+	text.Draw(screen, "Enter to accept, Escape to cancel", game.SmallFontFace, x, y, color.Gray{Y: 160})
+
+	if n.err != nil {
+		y += lineHeight
+		text.Draw(screen, n.err.Error(), game.SmallFontFace, x, y, color.RGBA{255, 80, 80, 255})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/bignum"
+	"Unbewohnte/capyclick/tween"
+	"time"
+)
+
+const rollUpTime time.Duration = time.Millisecond * 400
+
+// HUD number that rolls to its new value instead of jumping
+type RollingNumber struct {
+	from    bignum.Number
+	to      bignum.Number
+	shown   bignum.Number
+	roll    *tween.Tween
+	started bool
+}
+
+// Rolls towards target by delta, a new target starts the roll over from the shown number
+func (r *RollingNumber) Update(target bignum.Number, delta time.Duration) {
+	if !r.started {
+		// Nothing to roll from yet
+		r.started = true
+		r.from, r.to, r.shown = target, target, target
+		return
+	}
+
+	if target.Cmp(r.to) != 0 {
+		r.from = r.shown
+		r.to = target
+		r.roll = tween.New(0.0, 1.0, rollUpTime, tween.OutCubic)
+	}
+
+	if r.roll == nil {
+		return
+	}
+
+	r.roll.Update(delta)
+	progress := r.roll.Value()
+	if r.roll.Done() {
+		r.shown = r.to
+		r.roll = nil
+	} else if r.to.Cmp(r.from) >= 0 {
+		r.shown = r.from.Add(r.to.Sub(r.from).MulFloat(progress))
+	} else {
+		r.shown = r.from.Sub(r.from.Sub(r.to).MulFloat(progress))
+	}
+}
+
+// Returns the number to show
+func (r *RollingNumber) Value() bignum.Number {
+	return r.shown
+}
@@ -0,0 +1,128 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// A single screen of the game: the clicker itself, a menu, a dialog...
+type Scene interface {
+	Update(game *Game) error
+	Draw(game *Game, screen *ebiten.Image)
+	Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int)
+}
+
+// Scenes that only partially cover the screen. The scene below them is drawn first
+type Overlay interface {
+	Scene
+	isOverlay()
+}
+
+// Provides the default device-scaled layout
+type baseScene struct{}
+
+func (b baseScene) Layout(outsideWidth, outsideHeight int) (int, int) {
+	scaleFactor := ebiten.DeviceScaleFactor()
+	return int(float64(outsideWidth) * scaleFactor), int(float64(outsideHeight) * scaleFactor)
+}
+
+// Embed to make a scene an overlay
+type overlayScene struct {
+	baseScene
+}
+
+func (o overlayScene) isOverlay() {}
+
+// Stack of scenes, only the topmost one is updated
+type SceneStack struct {
+	scenes []Scene
+}
+
+func (s *SceneStack) Push(scene Scene) {
+	s.scenes = append(s.scenes, scene)
+}
+
+// Removes the topmost scene and returns it
+func (s *SceneStack) Pop() Scene {
+	if len(s.scenes) == 0 {
+		return nil
+	}
+
+	top := s.scenes[len(s.scenes)-1]
+	s.scenes = s.scenes[:len(s.scenes)-1]
+	return top
+}
+
+// Replaces the topmost scene with a new one
+func (s *SceneStack) Replace(scene Scene) {
+	s.Pop()
+	s.Push(scene)
+}
+
+// Drops all scenes and starts over with the given one
+func (s *SceneStack) Reset(scene Scene) {
+	s.scenes = []Scene{scene}
+}
+
+// Returns the topmost scene or nil if there are none
+func (s *SceneStack) Top() Scene {
+	if len(s.scenes) == 0 {
+		return nil
+	}
+
+	return s.scenes[len(s.scenes)-1]
+}
+
+func (s *SceneStack) Len() int {
+	return len(s.scenes)
+}
+
+func (s *SceneStack) Update(game *Game) error {
+	top := s.Top()
+	if top == nil {
+		return nil
+	}
+
+	return top.Update(game)
+}
+
+// Draws the topmost scene along with every scene it overlays
+func (s *SceneStack) Draw(game *Game, screen *ebiten.Image) {
+	bottom := len(s.scenes) - 1
+	for bottom > 0 {
+		if _, ok := s.scenes[bottom].(Overlay); !ok {
+			break
+		}
+		bottom--
+	}
+
+	for index := bottom; index >= 0 && index < len(s.scenes); index++ {
+		s.scenes[index].Draw(game, screen)
+	}
+}
+
+func (s *SceneStack) Layout(outsideWidth, outsideHeight int) (int, int) {
+	top := s.Top()
+	if top == nil {
+		return baseScene{}.Layout(outsideWidth, outsideHeight)
+	}
+
+	return top.Layout(outsideWidth, outsideHeight)
+}
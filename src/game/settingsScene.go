@@ -0,0 +1,136 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/input"
+	"Unbewohnte/capyclick/numfmt"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+type SettingsScene struct {
+	baseScene
+	menu *Menu
+}
+
+// Changes volume by given amount of 10% steps
+func adjustVolume(game *Game, steps int) {
+	volume := math.Round(game.Config.Volume*10.0+float64(steps)) / 10.0
+	game.SetVolume(math.Max(0.0, math.Min(1.0, volume)))
+}
+
+// Switches to the next or previous number notation
+func adjustNumberFormat(game *Game, direction int) {
+	notation := numfmt.Notation(game.Config.NumberFormat)
+	steps := 1
+	if direction < 0 {
+		steps = len(numfmt.Notations) - 1
+	}
+
+	for i := 0; i < steps; i++ {
+		notation = numfmt.Next(notation)
+	}
+	game.Config.NumberFormat = string(notation)
+}
+
+func NewSettingsScene() *SettingsScene {
+	return &SettingsScene{
+		menu: NewMenu(
+			MenuItem{
+				Label: func(game *Game) string {
+					return fmt.Sprintf("Volume: %d%%", int(math.Round(game.Config.Volume*100.0)))
+				},
+				Action: func(game *Game) error {
+					if game.Config.Volume >= 1.0 {
+						game.SetVolume(0.0)
+					} else {
+						adjustVolume(game, 2)
+					}
+					return nil
+				},
+				Adjust: func(game *Game, direction int) {
+					adjustVolume(game, direction)
+				},
+			},
+			MenuItem{
+				Label: func(game *Game) string {
+					return fmt.Sprintf("Numbers: %s", numfmt.Notation(game.Config.NumberFormat))
+				},
+				Action: func(game *Game) error {
+					adjustNumberFormat(game, 1)
+					return nil
+				},
+				Adjust: adjustNumberFormat,
+			},
+			MenuItem{
+				Label: func(game *Game) string {
+					if ebiten.IsFullscreen() {
+						return "Fullscreen: on"
+					}
+					return "Fullscreen: off"
+				},
+				Action: func(game *Game) error {
+					game.ToggleFullscreen()
+					return nil
+				},
+			},
+			MenuItem{
+				Label: staticLabel("Controls"),
+				Action: func(game *Game) error {
+					game.Scenes.Push(NewControlsScene())
+					return nil
+				},
+			},
+			MenuItem{
+				Label: staticLabel("Back"),
+				Action: func(game *Game) error {
+					game.Scenes.Pop()
+					return nil
+				},
+			},
+		),
+	}
+}
+
+func (s *SettingsScene) Update(game *Game) error {
+	if game.Bindings.JustPressed(input.Back) {
+		game.Scenes.Pop()
+		game.PlaySound("boop")
+		return nil
+	}
+
+	return s.menu.Update(game)
+}
+
+func (s *SettingsScene) Draw(game *Game, screen *ebiten.Image) {
+	game.DrawWorld(screen)
+
+	panel := menuPanel(screen)
+	drawPanel(screen, panel)
+
+	x := panel.Min.X + 20
+	y := panel.Min.Y + game.FontFace.Metrics().Height.Ceil()
+	text.Draw(screen, "Settings", game.FontFace, x, y, color.White)
+	s.menu.Draw(game, screen, x, y+game.FontFace.Metrics().Height.Ceil()/2)
+}
@@ -0,0 +1,173 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/input"
+	"Unbewohnte/capyclick/logger"
+	"Unbewohnte/capyclick/shop"
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Upgrade shop shown over the clicker
+type ShopScene struct {
+	overlayScene
+	Selected int
+	rows     []image.Rectangle
+	panel    image.Rectangle
+}
+
+func NewShopScene() *ShopScene {
+	return &ShopScene{
+		Selected: 0,
+		rows:     make([]image.Rectangle, len(shop.Catalog)),
+	}
+}
+
+// Tries to buy upgrade under given catalog index
+func (s *ShopScene) buy(game *Game, index int) {
+	if index < 0 || index >= len(shop.Catalog) {
+		return
+	}
+
+	upgrade := shop.Catalog[index]
+	err := shop.Buy(&game.Sim.Save, upgrade.ID)
+	if err != nil {
+		return
+	}
+
+	logger.Info("[Shop] Bought \"%s\" (now %d)", upgrade.Name, game.Sim.Save.Upgrades[upgrade.ID])
+	game.PlaySound("levelup")
+}
+
+func (s *ShopScene) Update(game *Game) error {
+	pressed := justPressedPoints(game.Bindings)
+	if game.Bindings.JustPressed(input.Back) ||
+		game.Bindings.JustPressed(input.Shop) ||
+		!s.panel.Empty() && anyPointOutside(pressed, s.panel) {
+		// Back to the capybara
+		game.Scenes.Pop()
+		game.PlaySound("boop")
+		return nil
+	}
+
+	if game.Bindings.JustPressed(input.Up) && s.Selected > 0 {
+		s.Selected--
+	}
+
+	if game.Bindings.JustPressed(input.Down) && s.Selected < len(shop.Catalog)-1 {
+		s.Selected++
+	}
+
+	if game.Bindings.JustPressed(input.Confirm) {
+		s.buy(game, s.Selected)
+	}
+
+	for _, point := range pressed {
+		for index, row := range s.rows {
+			if point.In(row) {
+				s.Selected = index
+				s.buy(game, index)
+			}
+		}
+	}
+
+	// The world does not stop while shopping
+	game.Tick(false)
+
+	return nil
+}
+
+func (s *ShopScene) Draw(game *Game, screen *ebiten.Image) {
+	margin := screen.Bounds().Dx() / 16
+	panel := image.Rect(
+		margin,
+		game.FontFace.Metrics().Height.Ceil()*3,
+		screen.Bounds().Dx()-margin,
+		screen.Bounds().Dy()-game.FontFace.Metrics().Height.Ceil()*3,
+	)
+	s.panel = panel
+
+	// Panel
+	vector.DrawFilledRect(
+		screen,
+		float32(panel.Min.X),
+		float32(panel.Min.Y),
+		float32(panel.Dx()),
+		float32(panel.Dy()),
+		color.RGBA{20, 12, 4, 220},
+		false,
+	)
+
+	lineHeight := game.SmallFontFace.Metrics().Height.Ceil()
+	y := panel.Min.Y + game.FontFace.Metrics().Height.Ceil()
+	text.Draw(screen, "Shop", game.FontFace, panel.Min.X+10, y, color.White)
+	y += lineHeight / 2
+
+	// Upgrades
+	for index, upgrade := range shop.Catalog {
+		owned := game.Sim.Save.Upgrades[upgrade.ID]
+		cost := upgrade.Cost(owned)
+
+		row := image.Rect(panel.Min.X, y, panel.Max.X, y+lineHeight*2+lineHeight/2)
+		s.rows[index] = row
+
+		if index == s.Selected {
+			vector.DrawFilledRect(
+				screen,
+				float32(row.Min.X),
+				float32(row.Min.Y),
+				float32(row.Dx()),
+				float32(row.Dy()),
+				color.RGBA{255, 165, 0, 60},
+				false,
+			)
+		}
+
+		var clr color.Color = color.White
+		if game.Sim.Save.Points.Cmp(cost) < 0 {
+			clr = color.Gray{Y: 140}
+		}
+
+		text.Draw(
+			screen,
+			fmt.Sprintf("%s [%s] - %s", upgrade.Name, game.FormatUint(uint64(owned)), game.FormatNumber(cost)),
+			game.SmallFontFace,
+			panel.Min.X+10,
+			y+lineHeight,
+			clr,
+		)
+		text.Draw(
+			screen,
+			upgrade.Description,
+			game.SmallFontFace,
+			panel.Min.X+20,
+			y+lineHeight*2,
+			clr,
+		)
+
+		y = row.Max.Y
+	}
+}
@@ -19,16 +19,18 @@
 package game
 
 import (
+	"Unbewohnte/capyclick/animation"
 	"Unbewohnte/capyclick/resources"
 	"image"
+	"math"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type AnimationData struct {
-	Squish              float64
-	Theta               float64
-	BounceDirectionFlag bool
+	Squish float64
+	Theta  float64
 }
 
 // Drawable image structure
@@ -39,29 +41,88 @@ type Sprite struct {
 	Animation AnimationData
 	Scale     float64
 	Dragged   bool
+	// Plays clips, the image follows the current frame
+	Animator animation.Player
 }
 
 func NewSprite(img image.Image) *Sprite {
+	return newSprite(ebiten.NewImageFromImage(img))
+}
+
+func newSprite(img *ebiten.Image) *Sprite {
 	return &Sprite{
-		Img: ebiten.NewImageFromImage(img),
+		Img: img,
 		X:   0.0,
 		Y:   0.0,
 		Animation: AnimationData{
-			Squish:              0.0,
-			Theta:               0.0,
-			BounceDirectionFlag: false,
+			Squish: 0.0,
+			Theta:  0.0,
 		},
-		Scale:   1.0,
-		Dragged: false,
+		Scale:    1.0,
+		Dragged:  false,
+		Animator: animation.Player{},
 	}
 }
 
 func NewSpriteFromFile(fileName string) *Sprite {
-	return NewSprite(resources.ImageFromFile(fileName))
+	return newSprite(resources.Image(fileName))
 }
 
+// Switches to another image, images are cached so this is cheap enough to do every frame
 func (s *Sprite) ChangeImageByName(fileName string) {
-	s.Img = ebiten.NewImageFromImage(resources.ImageFromFile(fileName))
+	s.Img = resources.Image(fileName)
+}
+
+// Starts playing the clip from its first frame, nil clips are ignored
+func (s *Sprite) Play(clip *animation.Clip) {
+	if clip == nil {
+		return
+	}
+
+	s.Animator.Play(clip)
+	s.showFrame()
+}
+
+// Moves the played clip on by delta and returns events of the frames reached
+func (s *Sprite) UpdateAnimation(delta time.Duration) []string {
+	events := s.Animator.Update(delta)
+	s.showFrame()
+	return events
+}
+
+// Switches the image to the current frame of the played clip
+func (s *Sprite) showFrame() {
+	frame, ok := s.Animator.Frame()
+	if !ok {
+		return
+	}
+
+	var img *ebiten.Image
+	if rect := frame.Rect(); rect.Empty() {
+		img = resources.Image(frame.Image)
+	} else {
+		img = resources.SubImage(frame.Image, rect)
+	}
+
+	if img != nil {
+		s.Img = img
+	}
+}
+
+// Applies the current frame's offset, scale and rotation around the image center.
+// Must go before the sprite is scaled and moved into place
+func (s *Sprite) applyFrame(op *ebiten.DrawImageOptions) {
+	frame, ok := s.Animator.Frame()
+	if !ok {
+		return
+	}
+
+	halfWidth := float64(s.Img.Bounds().Dx()) / 2.0
+	halfHeight := float64(s.Img.Bounds().Dy()) / 2.0
+	op.GeoM.Translate(-halfWidth, -halfHeight)
+	op.GeoM.Scale(frame.ScaleX, frame.ScaleY)
+	op.GeoM.Rotate(frame.Rotation * math.Pi / 180.0)
+	op.GeoM.Translate(halfWidth+frame.OffsetX, halfHeight+frame.OffsetY)
 }
 
 // Returns how big the image is with applied scale factor
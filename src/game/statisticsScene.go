@@ -0,0 +1,104 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/achievements"
+	"Unbewohnte/capyclick/input"
+	"Unbewohnte/capyclick/shop"
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Shows everything the save knows about the player
+type StatisticsScene struct {
+	baseScene
+	menu *Menu
+}
+
+func NewStatisticsScene() *StatisticsScene {
+	return &StatisticsScene{
+		menu: NewMenu(
+			MenuItem{
+				Label: staticLabel("Back"),
+				Action: func(game *Game) error {
+					game.Scenes.Pop()
+					return nil
+				},
+			},
+		),
+	}
+}
+
+func (s *StatisticsScene) Update(game *Game) error {
+	if game.Bindings.JustPressed(input.Back) {
+		game.Scenes.Pop()
+		game.PlaySound("boop")
+		return nil
+	}
+
+	return s.menu.Update(game)
+}
+
+func (s *StatisticsScene) Draw(game *Game, screen *ebiten.Image) {
+	game.DrawWorld(screen)
+
+	panel := menuPanel(screen)
+	drawPanel(screen, panel)
+
+	x := panel.Min.X + 20
+	y := panel.Min.Y + game.FontFace.Metrics().Height.Ceil()
+	text.Draw(screen, "Statistics", game.FontFace, x, y, color.White)
+
+	lines := []string{
+		fmt.Sprintf("Points: %s", game.FormatNumber(game.Sim.Save.Points)),
+		fmt.Sprintf("Lifetime points: %s", game.FormatNumber(game.Sim.Save.LifetimePoints)),
+		fmt.Sprintf("Level: %s", game.FormatUint(uint64(game.Sim.Save.Level))),
+		fmt.Sprintf("Clicks: %s", game.FormatUint(game.Sim.Save.TimesClicked)),
+		fmt.Sprintf("Points per click: %s", game.FormatNumber(shop.ClickPoints(game.Sim.Save))),
+		fmt.Sprintf("Points per second: %s", game.FormatNumber(shop.PassiveIncome(game.Sim.Save))),
+		fmt.Sprintf("Mandarin rains: %s", game.FormatUint(game.Sim.Save.MandarinRainsCompleted)),
+		fmt.Sprintf("Rebirths: %s", game.FormatUint(uint64(game.Sim.Save.Prestiges))),
+		fmt.Sprintf("Golden mandarins: %s", game.FormatNumber(game.Sim.Save.GoldenMandarins)),
+		fmt.Sprintf(
+			"Achievements: %s/%s",
+			game.FormatUint(uint64(len(game.Sim.Save.Achievements))),
+			game.FormatUint(uint64(len(achievements.List))),
+		),
+		fmt.Sprintf("Playtime: %s", time.Duration(game.Sim.Save.PlaytimeSeconds)*time.Second),
+		fmt.Sprintf("Playing since %s", time.Unix(int64(game.Sim.Save.CreatedUnix), 0).Format("2006-01-02")),
+	}
+
+	lineHeight := game.SmallFontFace.Metrics().Height.Ceil()
+	for _, line := range lines {
+		y += lineHeight
+		text.Draw(screen, line, game.SmallFontFace, x, y, color.White)
+	}
+
+	if game.Sim.Save.Modified {
+		y += lineHeight
+		text.Draw(screen, "Save was modified outside the game", game.SmallFontFace, x, y, color.RGBA{255, 80, 80, 255})
+	}
+
+	s.menu.Draw(game, screen, x, y+lineHeight/2)
+}
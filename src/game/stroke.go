@@ -19,6 +19,9 @@
 package game
 
 import (
+	"Unbewohnte/capyclick/input"
+	"image"
+
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
@@ -28,14 +31,17 @@ type StrokeSource interface {
 	IsJustReleased() bool
 }
 
-type MouseStrokeSource struct{}
+// Dragging with the mouse while the button bound to click is held
+type MouseStrokeSource struct {
+	Button input.Input
+}
 
 func (m *MouseStrokeSource) Position() (int, int) {
 	return ebiten.CursorPosition()
 }
 
 func (m *MouseStrokeSource) IsJustReleased() bool {
-	return inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft)
+	return m.Button.IsJustReleased()
 }
 
 type TouchStrokeSource struct {
@@ -50,6 +56,56 @@ func (t *TouchStrokeSource) IsJustReleased() bool {
 	return inpututil.IsTouchJustReleased(t.ID)
 }
 
+// Dragging with the gamepad's virtual cursor while the button is held
+type GamepadStrokeSource struct {
+	Cursor *VirtualCursor
+	Button input.Input
+}
+
+func (g *GamepadStrokeSource) Position() (int, int) {
+	return g.Cursor.Position()
+}
+
+func (g *GamepadStrokeSource) IsJustReleased() bool {
+	return g.Button.IsJustReleased()
+}
+
+// Returns positions of mouse clicks and touch presses that happened this tick
+func justPressedPoints(bindings input.Bindings) []image.Point {
+	var points []image.Point
+	if _, ok := bindings.JustPressedOn(input.Click, input.DeviceMouse); ok {
+		points = append(points, image.Pt(ebiten.CursorPosition()))
+	}
+
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		points = append(points, image.Pt(ebiten.TouchPosition(id)))
+	}
+
+	return points
+}
+
+// Returns true if any of the points is inside the rectangle
+func anyPointIn(points []image.Point, rect image.Rectangle) bool {
+	for _, point := range points {
+		if point.In(rect) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns true if any of the points is outside the rectangle
+func anyPointOutside(points []image.Point, rect image.Rectangle) bool {
+	for _, point := range points {
+		if !point.In(rect) {
+			return true
+		}
+	}
+
+	return false
+}
+
 type Stroke struct {
 	source   StrokeSource
 	offsetX  float64
@@ -73,15 +129,6 @@ func (s *Stroke) Update(game *Game) {
 		return
 	}
 
-	// xp, yp := s.source.Position()
-	// difference := newVec2f(
-	// 	(float64(xp-s.physical.Sprite.RealBounds().Dx()/2)-s.physical.Sprite.X)*3.5,
-	// 	(float64(yp-s.physical.Sprite.RealBounds().Dy()/2)-s.physical.Sprite.Y)*3.5,
-	// )
-
-	// s.physical.Acceleration.Vx = difference.Vx / s.physical.Mass
-	// s.physical.Acceleration.Vy = difference.Vy / s.physical.Mass
-
 	if s.source.IsJustReleased() {
 		s.physical.Sprite.Dragged = false
 		return
@@ -0,0 +1,58 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/physics"
+	"Unbewohnte/capyclick/timestep"
+	"testing"
+)
+
+type fakeStrokeSource struct {
+	x, y     int
+	released bool
+}
+
+func (f *fakeStrokeSource) Position() (int, int) {
+	return f.x, f.y
+}
+
+func (f *fakeStrokeSource) IsJustReleased() bool {
+	return f.released
+}
+
+func TestStrokeReleasedOnFrameWithoutSteps(t *testing.T) {
+	game := &Game{
+		Strokes: map[*Stroke]struct{}{},
+		Clock:   timestep.New(StepDuration, maxStepsPerFrame),
+	}
+
+	physical := &Physical{Sprite: newSprite(nil), Body: physics.NewBody(physics.Circle(1.0), 1.0)}
+	source := &fakeStrokeSource{}
+	game.Strokes[NewStroke(source, physical)] = struct{}{}
+
+	// Too short for a single step, so the world does not move this frame
+	game.FrameDelta = StepDuration / 4
+	source.released = true
+	game.Tick(false)
+
+	if physical.Sprite.Dragged || len(game.Strokes) != 0 {
+		t.Errorf("release on a frame without steps was missed")
+	}
+}
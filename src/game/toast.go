@@ -0,0 +1,106 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/tween"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	toastSlide time.Duration = time.Second / 3
+	toastStay  time.Duration = time.Second * 5 / 2
+)
+
+// Short notification that slides in from the right side of the screen
+type Toast struct {
+	Title string
+	Text  string
+	// How far the toast is slid in, goes beyond [0.0; 1.0] when it overshoots
+	visibility float64
+	// Slides the toast in and back out
+	slide tween.Animation
+}
+
+// Queue of toasts shown one after another
+type Toasts struct {
+	queue []*Toast
+}
+
+func (t *Toasts) Push(title string, text string) {
+	toast := &Toast{
+		Title:      title,
+		Text:       text,
+		visibility: 0.0,
+		slide:      nil,
+	}
+	toast.slide = tween.Sequence(
+		tween.To(&toast.visibility, 1.0, toastSlide, tween.OutBack),
+		tween.Delay(toastStay),
+		tween.To(&toast.visibility, 0.0, toastSlide, tween.InQuad),
+	)
+
+	t.queue = append(t.queue, toast)
+}
+
+// Slides the shown toast by delta
+func (t *Toasts) Update(delta time.Duration) {
+	if len(t.queue) == 0 {
+		return
+	}
+
+	current := t.queue[0]
+	current.slide.Update(delta)
+	if current.slide.Done() {
+		t.queue = t.queue[1:]
+	}
+}
+
+func (t *Toasts) Draw(screen *ebiten.Image, game *Game) {
+	if len(t.queue) == 0 {
+		return
+	}
+	current := t.queue[0]
+
+	lineHeight := game.SmallFontFace.Metrics().Height.Ceil()
+	width := screen.Bounds().Dx() / 2
+	height := lineHeight*2 + lineHeight/2
+
+	x := float64(screen.Bounds().Dx()) - float64(width)*current.visibility
+	y := screen.Bounds().Dy() / 4
+
+	vector.DrawFilledRect(
+		screen,
+		float32(x),
+		float32(y),
+		float32(width),
+		float32(height),
+		color.RGBA{20, 12, 4, 220},
+		false,
+	)
+	vector.DrawFilledRect(screen, float32(x), float32(y), 4, float32(height), color.RGBA{255, 165, 0, 255}, false)
+
+	text.Draw(screen, current.Title, game.SmallFontFace, int(x)+12, y+lineHeight, color.RGBA{255, 165, 0, 255})
+	text.Draw(screen, current.Text, game.SmallFontFace, int(x)+12, y+lineHeight*2, color.White)
+}
@@ -0,0 +1,284 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/input"
+	"Unbewohnte/capyclick/logger"
+	"Unbewohnte/capyclick/save"
+	"errors"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Moves progress between machines and builds of the game
+type TransferScene struct {
+	baseScene
+	menu          *Menu
+	message       string
+	messageIsFail bool
+}
+
+func NewTransferScene() *TransferScene {
+	scene := &TransferScene{}
+	scene.menu = NewMenu(
+		MenuItem{
+			Label: staticLabel("Export"),
+			Action: func(game *Game) error {
+				exported, err := save.Export(game.Sim.Save)
+				if err == nil {
+					scene.message, err = offerExport(game, exported)
+				}
+				if err != nil {
+					logger.Error("[Transfer] Failed to export: %s", err)
+					scene.message, scene.messageIsFail = err.Error(), true
+					return nil
+				}
+
+				scene.messageIsFail = false
+				logger.Info("[Transfer] Exported the save")
+				return nil
+			},
+		},
+		MenuItem{
+			Label: staticLabel("Import"),
+			Action: func(game *Game) error {
+				exported, err := requestImport(game)
+				if err != nil {
+					scene.message, scene.messageIsFail = err.Error(), true
+					return nil
+				}
+
+				imported, err := save.Import(exported)
+				var tamperedErr *save.TamperedError
+				tampered := errors.As(err, &tamperedErr)
+				if err != nil && !tampered {
+					logger.Warning("[Transfer] Failed to import: %s", err)
+					scene.message, scene.messageIsFail = err.Error(), true
+					return nil
+				}
+
+				scene.message = ""
+				game.Scenes.Push(NewImportPreviewScene(*imported, tampered))
+				return nil
+			},
+		},
+		MenuItem{
+			Label: staticLabel("Back"),
+			Action: func(game *Game) error {
+				game.Scenes.Pop()
+				return nil
+			},
+		},
+	)
+
+	return scene
+}
+
+func (t *TransferScene) Update(game *Game) error {
+	if game.Bindings.JustPressed(input.Back) {
+		game.Scenes.Pop()
+		game.PlaySound("boop")
+		return nil
+	}
+
+	return t.menu.Update(game)
+}
+
+func (t *TransferScene) Draw(game *Game, screen *ebiten.Image) {
+	game.DrawWorld(screen)
+
+	panel := menuPanel(screen)
+	drawPanel(screen, panel)
+
+	x := panel.Min.X + 20
+	y := panel.Min.Y + game.FontFace.Metrics().Height.Ceil()
+	text.Draw(screen, "Export / import", game.FontFace, x, y, color.White)
+
+	y += game.SmallFontFace.Metrics().Height.Ceil()
+	if t.message != "" {
+		var clr color.Color = color.Gray{Y: 160}
+		if t.messageIsFail {
+			clr = color.RGBA{255, 80, 80, 255}
+		}
+		text.Draw(screen, t.message, game.SmallFontFace, x, y, clr)
+	} else {
+		text.Draw(screen, "Take your capybara to another machine", game.SmallFontFace, x, y, color.Gray{Y: 160})
+	}
+
+	t.menu.Draw(game, screen, x, y+game.SmallFontFace.Metrics().Height.Ceil()/2)
+}
+
+// Compares an imported save with the current one before anything is overwritten
+type ImportPreviewScene struct {
+	baseScene
+	imported save.Save
+	tampered bool
+	menu     *Menu
+	message  string
+}
+
+// Returns true if the tamper policy would throw the imported progress away
+func (i *ImportPreviewScene) resets(game *Game) bool {
+	return i.tampered && save.TamperPolicy(game.Config.TamperPolicy) == save.TamperReset
+}
+
+// Makes the imported save ready to be played on this machine
+func (i *ImportPreviewScene) prepare(game *Game) save.Save {
+	imported := i.imported.Clone()
+	if i.tampered {
+		imported = save.HandleTampered(imported, save.TamperPolicy(game.Config.TamperPolicy))
+	}
+
+	// Time spent on another machine is not offline progress
+	now := uint64(time.Now().Unix())
+	imported.LastOpenedUnix = now
+	imported.LastSavedUnix = now
+
+	return imported
+}
+
+func NewImportPreviewScene(imported save.Save, tampered bool) *ImportPreviewScene {
+	scene := &ImportPreviewScene{
+		imported: imported,
+		tampered: tampered,
+	}
+
+	scene.menu = NewMenu(
+		MenuItem{
+			Label: func(game *Game) string {
+				if scene.resets(game) {
+					return "Replace current progress (not allowed)"
+				}
+				return "Replace current progress"
+			},
+			Action: func(game *Game) error {
+				if scene.resets(game) {
+					// Would swap real progress for a blank save
+					scene.message = "Modified saves start over, current progress is kept"
+					return nil
+				}
+
+				game.Sim.Save = scene.prepare(game)
+				game.ResetWorld()
+				game.Autosave("import")
+				game.Scenes.Reset(NewMainMenuScene())
+				logger.Info("[Transfer] Replaced current progress with an imported save")
+				return nil
+			},
+		},
+		MenuItem{
+			Label: staticLabel("Import as a new profile"),
+			Action: func(game *Game) error {
+				if game.Profiles == nil {
+					scene.message = "Profiles are not kept in this game"
+					return nil
+				}
+
+				slot, err := game.Profiles.Create("Imported", scene.prepare(game))
+				if err != nil {
+					scene.message = err.Error()
+					return nil
+				}
+
+				game.Scenes.Reset(NewMainMenuScene())
+				logger.Info("[Transfer] Imported a save as profile \"%s\"", slot.Name)
+				return nil
+			},
+		},
+		MenuItem{
+			Label: staticLabel("Cancel"),
+			Action: func(game *Game) error {
+				game.Scenes.Pop()
+				return nil
+			},
+		},
+	)
+
+	return scene
+}
+
+func (i *ImportPreviewScene) Update(game *Game) error {
+	if game.Bindings.JustPressed(input.Back) {
+		game.Scenes.Pop()
+		game.PlaySound("boop")
+		return nil
+	}
+
+	return i.menu.Update(game)
+}
+
+func (i *ImportPreviewScene) Draw(game *Game, screen *ebiten.Image) {
+	game.DrawWorld(screen)
+
+	panel := menuPanel(screen)
+	drawPanel(screen, panel)
+
+	x := panel.Min.X + 20
+	y := panel.Min.Y + game.FontFace.Metrics().Height.Ceil()
+	text.Draw(screen, "Import", game.FontFace, x, y, color.White)
+
+	// Shown as it would be played, after the tamper policy
+	current := game.Sim.Save
+	imported := i.prepare(game)
+	lines := [][3]string{
+		{"", "Now", "Imported"},
+		{
+			"Level",
+			game.FormatUint(uint64(current.Level)),
+			game.FormatUint(uint64(imported.Level)),
+		},
+		{"Points", game.FormatNumber(current.Points), game.FormatNumber(imported.Points)},
+		{"Lifetime", game.FormatNumber(current.LifetimePoints), game.FormatNumber(imported.LifetimePoints)},
+		{"Golden", game.FormatNumber(current.GoldenMandarins), game.FormatNumber(imported.GoldenMandarins)},
+		{"Clicks", game.FormatUint(current.TimesClicked), game.FormatUint(imported.TimesClicked)},
+		{
+			"Playtime",
+			(time.Duration(current.PlaytimeSeconds) * time.Second).String(),
+			(time.Duration(imported.PlaytimeSeconds) * time.Second).String(),
+		},
+	}
+
+	lineHeight := game.SmallFontFace.Metrics().Height.Ceil()
+	column := panel.Dx() / 3
+	for _, line := range lines {
+		y += lineHeight
+		for index, cell := range line {
+			text.Draw(screen, cell, game.SmallFontFace, x+column*index, y, color.White)
+		}
+	}
+
+	if i.resets(game) {
+		y += lineHeight
+		text.Draw(screen, "This save was modified outside the game and would start over", game.SmallFontFace, x, y, color.RGBA{255, 80, 80, 255})
+	} else if i.tampered {
+		y += lineHeight
+		text.Draw(screen, "This save was modified outside the game", game.SmallFontFace, x, y, color.RGBA{255, 80, 80, 255})
+	}
+
+	if i.message != "" {
+		y += lineHeight
+		text.Draw(screen, i.message, game.SmallFontFace, x, y, color.RGBA{255, 80, 80, 255})
+	}
+
+	i.menu.Draw(game, screen, x, y+lineHeight/2)
+}
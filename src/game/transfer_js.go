@@ -0,0 +1,42 @@
+//go:build js
+
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"errors"
+	"syscall/js"
+)
+
+// Shows the exported save in a browser dialog so it can be copied
+func offerExport(g *Game, exported string) (string, error) {
+	js.Global().Call("prompt", "Copy your save:", exported)
+	return "Paste the copied text into another game to import it", nil
+}
+
+// Asks to paste an exported save into a browser dialog
+func requestImport(g *Game) (string, error) {
+	pasted := js.Global().Call("prompt", "Paste an exported save:")
+	if pasted.IsNull() || pasted.IsUndefined() {
+		return "", errors.New("import was cancelled")
+	}
+
+	return pasted.String(), nil
+}
@@ -0,0 +1,58 @@
+//go:build !js
+
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"Unbewohnte/capyclick/storage"
+	"fmt"
+	"path/filepath"
+)
+
+// File exported saves are written to and imported from
+const ExportFileName string = "capyclickExport.txt"
+
+// Writes the exported save into a file next to the game
+func offerExport(g *Game, exported string) (string, error) {
+	err := storage.NewFiles(g.WorkingDir).Save(ExportFileName, []byte(exported))
+	if err != nil {
+		return "", err
+	}
+
+	path, err := filepath.Abs(filepath.Join(g.WorkingDir, ExportFileName))
+	if err != nil {
+		path = ExportFileName
+	}
+
+	return fmt.Sprintf("Saved to %s", path), nil
+}
+
+// Reads an exported save from the file next to the game
+func requestImport(g *Game) (string, error) {
+	data, err := storage.NewFiles(g.WorkingDir).Load(ExportFileName)
+	if storage.IsNotExist(err) {
+		return "", fmt.Errorf("put an exported save into %s first", ExportFileName)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
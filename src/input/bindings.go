@@ -0,0 +1,256 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package input
+
+import (
+	"fmt"
+)
+
+type Action string
+
+const (
+	// Gameplay
+	Click        Action = "click"
+	Pause        Action = "pause"
+	Shop         Action = "shop"
+	Achievements Action = "achievements"
+	Prestige     Action = "prestige"
+	VolumeUp     Action = "volumeUp"
+	VolumeDown   Action = "volumeDown"
+
+	// Menus and dialogs
+	Up      Action = "up"
+	Down    Action = "down"
+	Left    Action = "left"
+	Right   Action = "right"
+	Confirm Action = "confirm"
+	Back    Action = "back"
+
+	// Everywhere
+	Fullscreen Action = "fullscreen"
+)
+
+// Where an action is listened to. Actions of different contexts may share inputs
+type Context uint8
+
+const (
+	ContextGlobal Context = iota
+	ContextGameplay
+	ContextMenu
+)
+
+type ActionInfo struct {
+	Action  Action
+	Name    string
+	Context Context
+	Default []Input
+}
+
+// All actions in the order they are shown in settings
+var Actions = []ActionInfo{
+	{Click, "Click", ContextGameplay, []Input{"mouse:Left", "gamepad:RightBottom"}},
+	{Pause, "Pause", ContextGameplay, []Input{"key:Escape", "gamepad:CenterRight"}},
+	{Shop, "Shop", ContextGameplay, []Input{"key:S", "gamepad:RightTop"}},
+	{Achievements, "Achievements", ContextGameplay, []Input{"key:A", "gamepad:RightLeft"}},
+	{Prestige, "Rebirth", ContextGameplay, []Input{"key:P"}},
+	{VolumeUp, "Volume up", ContextGameplay, []Input{"key:ArrowRight"}},
+	{VolumeDown, "Volume down", ContextGameplay, []Input{"key:ArrowLeft"}},
+	{Up, "Menu up", ContextMenu, []Input{"key:ArrowUp", "gamepad:LeftTop"}},
+	{Down, "Menu down", ContextMenu, []Input{"key:ArrowDown", "gamepad:LeftBottom"}},
+	{Left, "Menu left", ContextMenu, []Input{"key:ArrowLeft", "gamepad:LeftLeft"}},
+	{Right, "Menu right", ContextMenu, []Input{"key:ArrowRight", "gamepad:LeftRight"}},
+	{Confirm, "Confirm", ContextMenu, []Input{"key:Enter", "key:Space", "gamepad:RightBottom"}},
+	{Back, "Back", ContextMenu, []Input{"key:Escape", "gamepad:RightRight"}},
+	{Fullscreen, "Fullscreen", ContextGlobal, []Input{"key:F12"}},
+}
+
+// Returns information about the action
+func Info(action Action) (ActionInfo, bool) {
+	for _, info := range Actions {
+		if info.Action == action {
+			return info, true
+		}
+	}
+
+	return ActionInfo{}, false
+}
+
+// Returns true if two actions can be triggered at the same time
+func sharesContext(a Action, b Action) bool {
+	infoA, _ := Info(a)
+	infoB, _ := Info(b)
+	return infoA.Context == ContextGlobal ||
+		infoB.Context == ContextGlobal ||
+		infoA.Context == infoB.Context
+}
+
+// Returned when an input is already used by another action in the same context
+type ConflictError struct {
+	Input  Input
+	Action Action
+}
+
+func (e *ConflictError) Error() string {
+	info, _ := Info(e.Action)
+	return fmt.Sprintf("%s is already used by \"%s\"", e.Input.Label(), info.Name)
+}
+
+// Inputs mapped to every action
+type Bindings map[Action][]Input
+
+// Returns bindings every action starts with
+func Default() Bindings {
+	bindings := make(Bindings, len(Actions))
+	for _, info := range Actions {
+		bindings[info.Action] = append([]Input(nil), info.Default...)
+	}
+
+	return bindings
+}
+
+// Builds bindings from configuration. Unknown actions and inputs are dropped,
+// actions missing in configuration keep their defaults
+func FromConfig(config map[string][]string) Bindings {
+	bindings := Default()
+	for name, inputs := range config {
+		action := Action(name)
+		if _, ok := Info(action); !ok {
+			continue
+		}
+
+		var valid []Input
+		for _, in := range inputs {
+			if Input(in).Validate() == nil {
+				valid = append(valid, Input(in))
+			}
+		}
+		bindings[action] = valid
+	}
+
+	return bindings
+}
+
+// Returns bindings in a form stored in configuration
+func (b Bindings) ToConfig() map[string][]string {
+	config := make(map[string][]string, len(b))
+	for action, inputs := range b {
+		for _, in := range inputs {
+			config[string(action)] = append(config[string(action)], string(in))
+		}
+	}
+
+	return config
+}
+
+// Returns an action of the same context already using the input, if any
+func (b Bindings) Conflict(action Action, in Input) (Action, bool) {
+	for _, info := range Actions {
+		if info.Action == action || !sharesContext(action, info.Action) {
+			continue
+		}
+
+		for _, bound := range b[info.Action] {
+			if bound == in {
+				return info.Action, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Binds the input to the action, replacing the action's previous input of the same device
+func (b Bindings) Bind(action Action, in Input) error {
+	err := in.Validate()
+	if err != nil {
+		return err
+	}
+
+	if other, ok := b.Conflict(action, in); ok {
+		return &ConflictError{Input: in, Action: other}
+	}
+
+	inputs := b[action]
+	for index, bound := range inputs {
+		if bound == in {
+			return nil
+		}
+
+		if bound.Device() == in.Device() {
+			inputs[index] = in
+			return nil
+		}
+	}
+	b[action] = append(inputs, in)
+
+	return nil
+}
+
+// Returns true if any input of the action was pressed during this tick
+func (b Bindings) JustPressed(action Action) bool {
+	for _, in := range b[action] {
+		if in.IsJustPressed() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the input of the action that was pressed during this tick, if any
+func (b Bindings) JustPressedInput(action Action) (Input, bool) {
+	for _, in := range b[action] {
+		if in.IsJustPressed() {
+			return in, true
+		}
+	}
+
+	return "", false
+}
+
+// Returns the input of the action from given device that was pressed during this tick, if any
+func (b Bindings) JustPressedOn(action Action, device string) (Input, bool) {
+	for _, in := range b[action] {
+		if in.Device() == device && in.IsJustPressed() {
+			return in, true
+		}
+	}
+
+	return "", false
+}
+
+// Returns true if any input of the action is being held down
+func (b Bindings) Pressed(action Action) bool {
+	for _, in := range b[action] {
+		if in.IsPressed() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the name of the first input bound to the action
+func (b Bindings) Label(action Action) string {
+	if len(b[action]) == 0 {
+		return "unbound"
+	}
+
+	return b[action][0].Label()
+}
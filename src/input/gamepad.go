@@ -0,0 +1,98 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package input
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Stick positions closer to the center than this are ignored
+const StickDeadZone float64 = 0.2
+
+// Calls check for every connected gamepad until it returns true
+func anyGamepad(check func(id ebiten.GamepadID) bool) bool {
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if check(id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Gamepads without a known standard mapping usually report their buttons in
+// the same order anyway, so the standard button index is used as a raw one
+func rawButton(button ebiten.StandardGamepadButton) ebiten.GamepadButton {
+	return ebiten.GamepadButton(button)
+}
+
+func isGamepadButtonPressed(id ebiten.GamepadID, button ebiten.StandardGamepadButton) bool {
+	if ebiten.IsStandardGamepadLayoutAvailable(id) {
+		return ebiten.IsStandardGamepadButtonPressed(id, button)
+	}
+
+	return ebiten.IsGamepadButtonPressed(id, rawButton(button))
+}
+
+func isGamepadButtonJustPressed(id ebiten.GamepadID, button ebiten.StandardGamepadButton) bool {
+	if ebiten.IsStandardGamepadLayoutAvailable(id) {
+		return inpututil.IsStandardGamepadButtonJustPressed(id, button)
+	}
+
+	return inpututil.IsGamepadButtonJustPressed(id, rawButton(button))
+}
+
+func isGamepadButtonJustReleased(id ebiten.GamepadID, button ebiten.StandardGamepadButton) bool {
+	if ebiten.IsStandardGamepadLayoutAvailable(id) {
+		return inpututil.IsStandardGamepadButtonJustReleased(id, button)
+	}
+
+	return inpututil.IsGamepadButtonJustReleased(id, rawButton(button))
+}
+
+// Returns true if at least one gamepad is connected
+func GamepadConnected() bool {
+	return len(ebiten.AppendGamepadIDs(nil)) != 0
+}
+
+// Returns the left stick position of the first gamepad that has it tilted,
+// each axis is in [-1; 1] range with the dead zone already cut off
+func LeftStick() (float64, float64) {
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		var x, y float64
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			x = ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+			y = ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+		} else if ebiten.GamepadAxisCount(id) >= 2 {
+			x = ebiten.GamepadAxisValue(id, 0)
+			y = ebiten.GamepadAxisValue(id, 1)
+		}
+
+		if math.Hypot(x, y) < StickDeadZone {
+			continue
+		}
+
+		return x, y
+	}
+
+	return 0.0, 0.0
+}
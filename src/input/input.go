@@ -0,0 +1,248 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package input
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// A single key or button written as "device:name", e.g. "key:Escape",
+// "mouse:Left" or "gamepad:RightBottom"
+type Input string
+
+const (
+	DeviceKey     string = "key"
+	DeviceMouse   string = "mouse"
+	DeviceGamepad string = "gamepad"
+)
+
+var ErrInvalidInput error = errors.New("invalid input")
+
+var mouseButtons = map[string]ebiten.MouseButton{
+	"Left":   ebiten.MouseButtonLeft,
+	"Middle": ebiten.MouseButtonMiddle,
+	"Right":  ebiten.MouseButtonRight,
+	"Back":   ebiten.MouseButton3,
+	"Next":   ebiten.MouseButton4,
+}
+
+// Standard layout names, see https://w3c.github.io/gamepad/#remapping
+var gamepadButtons = map[string]ebiten.StandardGamepadButton{
+	"RightBottom":      ebiten.StandardGamepadButtonRightBottom,
+	"RightRight":       ebiten.StandardGamepadButtonRightRight,
+	"RightLeft":        ebiten.StandardGamepadButtonRightLeft,
+	"RightTop":         ebiten.StandardGamepadButtonRightTop,
+	"FrontTopLeft":     ebiten.StandardGamepadButtonFrontTopLeft,
+	"FrontTopRight":    ebiten.StandardGamepadButtonFrontTopRight,
+	"FrontBottomLeft":  ebiten.StandardGamepadButtonFrontBottomLeft,
+	"FrontBottomRight": ebiten.StandardGamepadButtonFrontBottomRight,
+	"CenterLeft":       ebiten.StandardGamepadButtonCenterLeft,
+	"CenterRight":      ebiten.StandardGamepadButtonCenterRight,
+	"LeftStick":        ebiten.StandardGamepadButtonLeftStick,
+	"RightStick":       ebiten.StandardGamepadButtonRightStick,
+	"LeftTop":          ebiten.StandardGamepadButtonLeftTop,
+	"LeftBottom":       ebiten.StandardGamepadButtonLeftBottom,
+	"LeftLeft":         ebiten.StandardGamepadButtonLeftLeft,
+	"LeftRight":        ebiten.StandardGamepadButtonLeftRight,
+	"CenterCenter":     ebiten.StandardGamepadButtonCenterCenter,
+}
+
+func Key(key ebiten.Key) Input {
+	return Input(DeviceKey + ":" + key.String())
+}
+
+func Mouse(button ebiten.MouseButton) Input {
+	for name, b := range mouseButtons {
+		if b == button {
+			return Input(DeviceMouse + ":" + name)
+		}
+	}
+
+	return ""
+}
+
+func Gamepad(button ebiten.StandardGamepadButton) Input {
+	for name, b := range gamepadButtons {
+		if b == button {
+			return Input(DeviceGamepad + ":" + name)
+		}
+	}
+
+	return ""
+}
+
+// Splits input into device and name parts
+func (i Input) split() (string, string) {
+	device, name, _ := strings.Cut(string(i), ":")
+	return device, name
+}
+
+func (i Input) Device() string {
+	device, _ := i.split()
+	return device
+}
+
+// Returns an error if the input does not name a real key or button
+func (i Input) Validate() error {
+	device, name := i.split()
+	switch device {
+	case DeviceKey:
+		var key ebiten.Key
+		return key.UnmarshalText([]byte(name))
+	case DeviceMouse:
+		if _, ok := mouseButtons[name]; ok {
+			return nil
+		}
+	case DeviceGamepad:
+		if _, ok := gamepadButtons[name]; ok {
+			return nil
+		}
+	}
+
+	return ErrInvalidInput
+}
+
+// Prettier names for keys whose names are too long for the HUD
+var keyLabels = map[string]string{
+	"ArrowLeft":  "←",
+	"ArrowRight": "→",
+	"ArrowUp":    "↑",
+	"ArrowDown":  "↓",
+}
+
+// Returns a short human-readable name
+func (i Input) Label() string {
+	device, name := i.split()
+	switch device {
+	case DeviceKey:
+		if label, ok := keyLabels[name]; ok {
+			return label
+		}
+		return name
+	case DeviceMouse:
+		return "Mouse " + name
+	case DeviceGamepad:
+		return "Pad " + name
+	default:
+		return name
+	}
+}
+
+// Returns true if the input was pressed during this tick
+func (i Input) IsJustPressed() bool {
+	device, name := i.split()
+	switch device {
+	case DeviceKey:
+		var key ebiten.Key
+		if key.UnmarshalText([]byte(name)) != nil {
+			return false
+		}
+		return inpututil.IsKeyJustPressed(key)
+
+	case DeviceMouse:
+		button, ok := mouseButtons[name]
+		return ok && inpututil.IsMouseButtonJustPressed(button)
+
+	case DeviceGamepad:
+		button, ok := gamepadButtons[name]
+		return ok && anyGamepad(func(id ebiten.GamepadID) bool {
+			return isGamepadButtonJustPressed(id, button)
+		})
+	}
+
+	return false
+}
+
+// Returns true if the input is being held down
+func (i Input) IsPressed() bool {
+	device, name := i.split()
+	switch device {
+	case DeviceKey:
+		var key ebiten.Key
+		if key.UnmarshalText([]byte(name)) != nil {
+			return false
+		}
+		return ebiten.IsKeyPressed(key)
+
+	case DeviceMouse:
+		button, ok := mouseButtons[name]
+		return ok && ebiten.IsMouseButtonPressed(button)
+
+	case DeviceGamepad:
+		button, ok := gamepadButtons[name]
+		return ok && anyGamepad(func(id ebiten.GamepadID) bool {
+			return isGamepadButtonPressed(id, button)
+		})
+	}
+
+	return false
+}
+
+// Returns true if the input was released during this tick
+func (i Input) IsJustReleased() bool {
+	device, name := i.split()
+	switch device {
+	case DeviceKey:
+		var key ebiten.Key
+		if key.UnmarshalText([]byte(name)) != nil {
+			return false
+		}
+		return inpututil.IsKeyJustReleased(key)
+
+	case DeviceMouse:
+		button, ok := mouseButtons[name]
+		return ok && inpututil.IsMouseButtonJustReleased(button)
+
+	case DeviceGamepad:
+		button, ok := gamepadButtons[name]
+		return ok && anyGamepad(func(id ebiten.GamepadID) bool {
+			return isGamepadButtonJustReleased(id, button)
+		})
+	}
+
+	return false
+}
+
+// Returns the first input pressed during this tick, if there is one. Used to capture new bindings
+func JustPressedInput() (Input, bool) {
+	keys := inpututil.AppendJustPressedKeys(nil)
+	if len(keys) != 0 {
+		return Key(keys[0]), true
+	}
+
+	for _, button := range mouseButtons {
+		if inpututil.IsMouseButtonJustPressed(button) {
+			return Mouse(button), true
+		}
+	}
+
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		for _, button := range gamepadButtons {
+			if isGamepadButtonJustPressed(id, button) {
+				return Gamepad(button), true
+			}
+		}
+	}
+
+	return "", false
+}
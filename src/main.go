@@ -21,16 +21,21 @@ package main
 import (
 	"Unbewohnte/capyclick/conf"
 	"Unbewohnte/capyclick/game"
+	"Unbewohnte/capyclick/input"
 	"Unbewohnte/capyclick/logger"
+	"Unbewohnte/capyclick/migration"
 	"Unbewohnte/capyclick/resources"
 	"Unbewohnte/capyclick/save"
+	"Unbewohnte/capyclick/storage"
 	"Unbewohnte/capyclick/util"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"runtime"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -46,9 +51,67 @@ var (
 
 const (
 	ConfigurationFileName string = "capyclickConfig.json"
-	SaveFileName          string = "capyclickSave.json"
+	ProfilesDirName       string = "capyclickSaves"
+	// The single save file of older versions
+	SaveFileName string = "capyclickSave.json"
 )
 
+// Copies an unreadable value aside so that a fresh one does not overwrite it
+func setAside(store storage.Store, key string) {
+	data, err := store.Load(key)
+	if err != nil {
+		logger.Error("[Init] Failed to set \"%s\" aside: %s", key, err)
+		return
+	}
+
+	damagedKey := fmt.Sprintf("%s.damaged-%d", key, time.Now().Unix())
+	err = store.Save(damagedKey, data)
+	if err != nil {
+		logger.Error("[Init] Failed to set \"%s\" aside: %s", key, err)
+		return
+	}
+	logger.Info("[Init] Kept unreadable \"%s\" as \"%s\"", key, damagedKey)
+}
+
+// Moves the single save of older versions into the first profile
+func importLegacySave(store storage.Store, key string, profiles *save.Profiles, policy save.TamperPolicy) {
+	legacySave, err := save.Load(store, key)
+	if storage.IsNotExist(err) {
+		return
+	}
+
+	var tamperedErr *save.TamperedError
+	if errors.As(err, &tamperedErr) {
+		logger.Warning("[Init] %s", err)
+		handled := save.HandleTampered(*legacySave, policy)
+		legacySave = &handled
+		err = nil
+	}
+	if err != nil {
+		logger.Error("[Init] Failed to import \"%s\": %s", key, err)
+		return
+	}
+
+	_, err = profiles.Create("Player 1", *legacySave)
+	if err != nil {
+		logger.Error("[Init] Failed to import \"%s\": %s", key, err)
+		return
+	}
+
+	// Keep the old save around but never import it twice
+	data, err := store.Load(key)
+	if err == nil {
+		err = store.Save(key+".imported", data)
+	}
+	if err == nil {
+		err = store.Delete(key)
+	}
+	if err != nil {
+		logger.Warning("[Init] Failed to put imported \"%s\" away: %s", key, err)
+	}
+	logger.Info("[Init] Imported \"%s\" as a profile", key)
+}
+
 func main() {
 	// Set logging output
 	logger.SetOutput(os.Stdout)
@@ -69,6 +132,9 @@ func main() {
 	// Create a game instance
 	var game game.Game = game.NewGame()
 
+	// Browsers always have somewhere to keep progress, desktop only does when asked to
+	persistent := *saveFiles || runtime.GOOS == "js"
+
 	if *saveFiles {
 		// Work out working directory
 		exeDir, err := os.Executable()
@@ -81,18 +147,31 @@ func main() {
 		game.WorkingDir = ""
 	}
 
-	if *saveFiles {
-		// Open/Create configuration file
+	if persistent {
+		game.Store = storage.Default(game.WorkingDir)
+
+		// Open/Create configuration
 		var config *conf.Configuration
-		config, err := conf.FromFile(filepath.Join(game.WorkingDir, ConfigurationFileName))
+		config, err := conf.Load(game.Store, ConfigurationFileName)
 		if err != nil {
-			err = conf.Create(filepath.Join(game.WorkingDir, ConfigurationFileName), game.Config)
+			var newerErr *migration.NewerVersionError
+			if errors.As(err, &newerErr) {
+				// Never overwrite progress made in a newer game
+				logger.Error("[Init] %s", err)
+				os.Exit(1)
+			}
+
+			if !storage.IsNotExist(err) {
+				logger.Error("[Init] Configuration and its backup are unreadable: %s", err)
+				setAside(game.Store, ConfigurationFileName)
+			}
+			err = conf.Write(game.Store, ConfigurationFileName, game.Config)
 			if err != nil {
-				logger.Error("[Init] Failed to create a new configuration file: %s", err)
+				logger.Error("[Init] Failed to create a new configuration: %s", err)
 				os.Exit(1)
 			}
-			logger.Info("[Init] Created a new configuration file")
-			// Proceed with a newly created configuration file
+			logger.Info("[Init] Created a new configuration")
+			// Proceed with a newly created configuration
 		}
 
 		// Replace default config with an opened one (if exists)
@@ -114,26 +193,27 @@ func main() {
 	ebiten.SetWindowPosition(game.Config.LastWindowPosition[0], game.Config.LastWindowPosition[1])
 	ebiten.SetWindowTitle(fmt.Sprintf("Capyclick %s", Version))
 
-	if *saveFiles {
-		// Open/Create save file
-		gameSave, err := save.FromFile(filepath.Join(game.WorkingDir, SaveFileName))
+	if persistent {
+		// Open profiles
+		profiles, err := save.OpenProfiles(storage.DefaultProfiles(filepath.Join(game.WorkingDir, ProfilesDirName)))
 		if err != nil {
-			err = save.Create(filepath.Join(game.WorkingDir, SaveFileName), game.Save)
-			if err != nil {
-				logger.Error("[Init] Failed to create a new save file \"%s\": %s", SaveFileName, err)
-				os.Exit(1)
-			}
-			logger.Info("[Init] Created a new save file \"%s\"", SaveFileName)
-			// Proceed with a new save file
+			logger.Error("[Init] Failed to open profiles: %s", err)
+			os.Exit(1)
 		}
+		game.Profiles = profiles
 
-		// Replace a blank save with an existing one (if exists)
-		if gameSave != nil {
-			gameSave.LastOpenedUnix = uint64(time.Now().Unix())
-			game.Save = *gameSave
+		if len(profiles.List()) == 0 {
+			importLegacySave(game.Store, SaveFileName, profiles, save.TamperPolicy(game.Config.TamperPolicy))
 		}
+
+		// Let the player pick who is playing
+		game.PickProfile()
+		game.StartAutosave()
 	}
 
+	// Apply saved key bindings
+	game.Bindings = input.FromConfig(game.Config.KeyBindings)
+
 	// Set each player's volume to the saved value
 	for _, player := range game.AudioPlayers {
 		player.SetVolume(game.Config.Volume)
@@ -146,8 +226,9 @@ func main() {
 	err := ebiten.RunGame(&game)
 	if err == ebiten.Termination || err == nil {
 		logger.Info("[Main] Shutting down!")
-		if *saveFiles {
-			game.SaveData(SaveFileName, ConfigurationFileName)
+		if persistent {
+			game.SaveData(ConfigurationFileName)
+			game.StopAutosave()
 		}
 		os.Exit(0)
 	} else {
@@ -0,0 +1,124 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package migration
+
+import (
+	"Unbewohnte/capyclick/storage"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Upgrades fields of a file from one version to the next
+type Step func(fields map[string]json.RawMessage) error
+
+// Migration steps of a single file format
+type Registry struct {
+	// What kind of file it is, used in error messages
+	Name string
+	// JSON field the version is stored in
+	VersionField string
+	// Version the game writes
+	Current uint8
+	// Steps keyed by the version they upgrade from
+	Steps map[uint8]Step
+}
+
+var ErrMissingStep error = errors.New("no migration step")
+
+// Returned when a file was written by a newer version of the game.
+// Such files are never replaced with their backups
+type NewerVersionError struct {
+	Name      string
+	Version   uint8
+	Supported uint8
+}
+
+func (e *NewerVersionError) Error() string {
+	return fmt.Sprintf(
+		"%s version %d is newer than version %d this game supports, please update the game",
+		e.Name, e.Version, e.Supported,
+	)
+}
+
+func (e *NewerVersionError) Unwrap() error {
+	return storage.ErrNoFallback
+}
+
+// Returns the version stored in the fields. Files without one are of the very first version
+func (r Registry) version(fields map[string]json.RawMessage) (uint8, error) {
+	raw, ok := fields[r.VersionField]
+	if !ok {
+		return 1, nil
+	}
+
+	var version uint8
+	err := json.Unmarshal(raw, &version)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s version: %w", r.Name, err)
+	}
+
+	if version == 0 {
+		return 1, nil
+	}
+
+	return version, nil
+}
+
+// Upgrades JSON data of any older version to the current one step by step.
+// Current data is returned as is
+func (r Registry) Apply(data []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := r.version(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if version > r.Current {
+		return nil, &NewerVersionError{Name: r.Name, Version: version, Supported: r.Current}
+	}
+
+	if version == r.Current {
+		return data, nil
+	}
+
+	for ; version < r.Current; version++ {
+		step, ok := r.Steps[version]
+		if !ok {
+			return nil, fmt.Errorf("%w from %s version %d", ErrMissingStep, r.Name, version)
+		}
+
+		err = step(fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate %s from version %d: %w", r.Name, version, err)
+		}
+	}
+
+	fields[r.VersionField], err = json.Marshal(r.Current)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(fields)
+}
@@ -0,0 +1,116 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package migration
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func appendStep(mark string) Step {
+	return func(fields map[string]json.RawMessage) error {
+		var trail string
+		if raw, ok := fields["trail"]; ok {
+			json.Unmarshal(raw, &trail)
+		}
+
+		var err error
+		fields["trail"], err = json.Marshal(trail + mark)
+		return err
+	}
+}
+
+var testRegistry = Registry{
+	Name:         "test",
+	VersionField: "version",
+	Current:      4,
+	Steps: map[uint8]Step{
+		1: appendStep("a"),
+		2: appendStep("b"),
+		3: appendStep("c"),
+	},
+}
+
+func TestApplyRunsStepsInOrder(t *testing.T) {
+	tests := []struct {
+		data  string
+		trail string
+	}{
+		{`{"version": 1}`, "abc"},
+		{`{"version": 2}`, "bc"},
+		{`{"version": 3}`, "c"},
+		// No version means the first one
+		{`{}`, "abc"},
+		{`{"version": 0}`, "abc"},
+	}
+
+	for _, test := range tests {
+		migrated, err := testRegistry.Apply([]byte(test.data))
+		if err != nil {
+			t.Fatalf("%s: %s", test.data, err)
+		}
+
+		var result struct {
+			Version uint8  `json:"version"`
+			Trail   string `json:"trail"`
+		}
+		err = json.Unmarshal(migrated, &result)
+		if err != nil {
+			t.Fatalf("%s: %s", test.data, err)
+		}
+
+		if result.Version != testRegistry.Current {
+			t.Errorf("%s: version is %d, expected %d", test.data, result.Version, testRegistry.Current)
+		}
+		if result.Trail != test.trail {
+			t.Errorf("%s: steps ran as %q, expected %q", test.data, result.Trail, test.trail)
+		}
+	}
+}
+
+func TestApplyKeepsCurrentData(t *testing.T) {
+	data := []byte(`{"version": 4, "trail": "x"}`)
+	migrated, err := testRegistry.Apply(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(migrated) != string(data) {
+		t.Errorf("current data was changed: %s", migrated)
+	}
+}
+
+func TestApplyRejectsNewerVersion(t *testing.T) {
+	_, err := testRegistry.Apply([]byte(`{"version": 5}`))
+	var newerErr *NewerVersionError
+	if !errors.As(err, &newerErr) {
+		t.Fatalf("expected a newer version error, got %v", err)
+	}
+}
+
+func TestApplyMissingStep(t *testing.T) {
+	registry := testRegistry
+	registry.Steps = map[uint8]Step{1: appendStep("a")}
+
+	_, err := registry.Apply([]byte(`{"version": 1}`))
+	if !errors.Is(err, ErrMissingStep) {
+		t.Fatalf("expected a missing step error, got %v", err)
+	}
+}
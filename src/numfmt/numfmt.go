@@ -0,0 +1,129 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package numfmt
+
+import (
+	"Unbewohnte/capyclick/bignum"
+	"fmt"
+	"strings"
+)
+
+type Notation string
+
+const (
+	// 1.23K, 45.6M, 789aa
+	Short Notation = "short"
+	// 1.23e45
+	Scientific Notation = "scientific"
+	// 12.3e6, exponent is always a multiple of 3
+	Engineering Notation = "engineering"
+	// 1,234,567
+	Full Notation = "full"
+)
+
+// All notations in the order they are cycled through
+var Notations = []Notation{Short, Scientific, Engineering, Full}
+
+// How many significant digits are shown in shortened notations
+const significantDigits int = 3
+
+// Suffixes for thousand powers before two-letter ones kick in
+var namedSuffixes = []string{"K", "M", "B", "T"}
+
+// Returns the notation following given one
+func Next(notation Notation) Notation {
+	for index, n := range Notations {
+		if n == notation {
+			return Notations[(index+1)%len(Notations)]
+		}
+	}
+
+	return Short
+}
+
+// Returns a suffix for given power of a thousand (1 -> K, 5 -> aa, 6 -> ab, ...).
+// Returns false when there is no suffix big enough
+func suffix(thousands int) (string, bool) {
+	if thousands <= 0 {
+		return "", true
+	}
+
+	if thousands <= len(namedSuffixes) {
+		return namedSuffixes[thousands-1], true
+	}
+
+	index := thousands - len(namedSuffixes) - 1
+	if index >= 26*26 {
+		return "", false
+	}
+
+	return string([]byte{byte('a' + index/26), byte('a' + index%26)}), true
+}
+
+// Returns significant digits with a decimal point after the first intDigits of them
+func mantissa(digits string, intDigits int) string {
+	count := significantDigits
+	if count < intDigits {
+		count = intDigits
+	}
+	if count > len(digits) {
+		count = len(digits)
+	}
+
+	whole := digits[:intDigits]
+	fraction := strings.TrimRight(digits[intDigits:count], "0")
+	if fraction == "" {
+		return whole
+	}
+
+	return whole + "." + fraction
+}
+
+// Formats the number according to the notation. Numbers below a thousand are always shown as is
+func Format(n bignum.Number, notation Notation) string {
+	digits := n.String()
+	exponent := len(digits) - 1
+	if exponent < 3 {
+		return digits
+	}
+
+	switch notation {
+	case Full:
+		return n.Grouped()
+
+	case Scientific:
+		return fmt.Sprintf("%se%d", mantissa(digits, 1), exponent)
+
+	case Engineering:
+		return fmt.Sprintf("%se%d", mantissa(digits, exponent%3+1), exponent-exponent%3)
+
+	default:
+		sfx, ok := suffix(exponent / 3)
+		if !ok {
+			// Ran out of suffixes
+			return Format(n, Scientific)
+		}
+		return mantissa(digits, exponent%3+1) + sfx
+	}
+}
+
+// Formats a plain integer according to the notation
+func FormatUint(value uint64, notation Notation) string {
+	return Format(bignum.New(value), notation)
+}
@@ -0,0 +1,117 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package numfmt
+
+import (
+	"Unbewohnte/capyclick/bignum"
+	"strings"
+	"testing"
+)
+
+func mustParse(t *testing.T, s string) bignum.Number {
+	t.Helper()
+	n, err := bignum.Parse(s)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %s", s, err)
+	}
+	return n
+}
+
+// Returns 10^exponent as decimal digits
+func power(exponent int) string {
+	return "1" + strings.Repeat("0", exponent)
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		n        string
+		notation Notation
+		want     string
+	}{
+		// Small numbers are never shortened
+		{"0", Short, "0"},
+		{"999", Short, "999"},
+		{"999", Scientific, "999"},
+		{"999", Full, "999"},
+
+		// Digits are cut off, not rounded
+		{"1000", Short, "1K"},
+		{"1234", Short, "1.23K"},
+		{"1999", Short, "1.99K"},
+		{"1050", Short, "1.05K"},
+		{"12345", Short, "12.3K"},
+		{"123456", Short, "123K"},
+		{"999999", Short, "999K"},
+		{"1234567", Short, "1.23M"},
+		{power(9), Short, "1B"},
+		{power(12), Short, "1T"},
+
+		// Two-letter suffixes follow T
+		{power(15), Short, "1aa"},
+		{"1234" + strings.Repeat("0", 14), Short, "123aa"},
+		{power(18), Short, "1ab"},
+		{power(15 + 26*3), Short, "1ba"},
+		{power(15 + (26*26-1)*3), Short, "1zz"},
+		{"9999" + strings.Repeat("0", 15+(26*26-1)*3-1), Short, "999zz"},
+
+		// Past zz there are no suffixes left
+		{power(15 + 26*26*3), Short, "1e2043"},
+
+		{"1234", Scientific, "1.23e3"},
+		{"1999", Scientific, "1.99e3"},
+		{power(45), Scientific, "1e45"},
+		{"1234", Engineering, "1.23e3"},
+		{"12345", Engineering, "12.3e3"},
+		{"123456", Engineering, "123e3"},
+		{"1234567", Engineering, "1.23e6"},
+		{"1234567", Full, "1,234,567"},
+
+		// Unknown notations fall back to the short one
+		{"1234", Notation("fancy"), "1.23K"},
+	}
+
+	for _, test := range tests {
+		if got := Format(mustParse(t, test.n), test.notation); got != test.want {
+			t.Errorf("%s in %s: expected %s, got %s", test.n, test.notation, test.want, got)
+		}
+	}
+}
+
+func TestFormatUint(t *testing.T) {
+	if got := FormatUint(18446744073709551615, Short); got != "18.4ab" {
+		t.Errorf("expected 18.4ab, got %s", got)
+	}
+	if got := FormatUint(42, Full); got != "42" {
+		t.Errorf("expected 42, got %s", got)
+	}
+}
+
+func TestNext(t *testing.T) {
+	notation := Short
+	for range Notations {
+		notation = Next(notation)
+	}
+	if notation != Short {
+		t.Errorf("notations do not cycle back, ended at %s", notation)
+	}
+
+	if Next(Notation("fancy")) != Short {
+		t.Errorf("unknown notation does not start over")
+	}
+}
@@ -0,0 +1,254 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package particles
+
+import (
+	"Unbewohnte/capyclick/physics"
+	"Unbewohnte/capyclick/tween"
+	"image/color"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Value changing over the life of a particle
+type Curve struct {
+	From float64
+	To   float64
+	Ease tween.Easing
+}
+
+// Returns a curve that stays at the value
+func Constant(value float64) Curve {
+	return Curve{
+		From: value,
+		To:   value,
+		Ease: tween.Linear,
+	}
+}
+
+// Returns the value of the curve at t in [0.0; 1.0] of the life
+func (c Curve) At(t float64) float64 {
+	ease := c.Ease
+	if ease == nil {
+		ease = tween.Linear
+	}
+
+	return c.From + (c.To-c.From)*ease(t)
+}
+
+// Describes particles to emit and emits them at a rate
+type Emitter struct {
+	Position physics.Vec2
+	// Particles spawn anywhere within this distance of the position
+	Radius float64
+	// Particles per second, 0 emits only bursts
+	Rate float64
+	// How long to emit at the rate, 0 is forever
+	Duration       time.Duration
+	Lifetime       time.Duration
+	LifetimeSpread time.Duration
+	// Pixels per second
+	Speed       float64
+	SpeedSpread float64
+	// Radians, 0 points right, -math.Pi/2 up
+	Direction float64
+	// Width of the cone of directions in radians, 2*math.Pi spreads all around
+	Spread float64
+	// Pixels per second squared
+	Gravity physics.Vec2
+	Fade    Curve
+	Scale   Curve
+	// One of them is picked for each particle
+	Images []string
+	Colors []color.RGBA
+	// Emission state
+	elapsed time.Duration
+	pending float64
+	stopped bool
+}
+
+// Stops emitting at the rate, emitted particles live on
+func (e *Emitter) Stop() {
+	e.stopped = true
+}
+
+// Returns true if the emitter will not emit at the rate anymore
+func (e *Emitter) Stopped() bool {
+	return e.stopped || e.Rate <= 0.0 || (e.Duration > 0 && e.elapsed >= e.Duration)
+}
+
+type Particle struct {
+	Position physics.Vec2
+	Velocity physics.Vec2
+	Image    string
+	Color    color.RGBA
+	Age      time.Duration
+	Lifetime time.Duration
+	emitter  *Emitter
+}
+
+// Returns how much of its life the particle has lived in [0.0; 1.0]
+func (p *Particle) Life() float64 {
+	if p.Lifetime <= 0 {
+		return 1.0
+	}
+
+	return math.Min(float64(p.Age)/float64(p.Lifetime), 1.0)
+}
+
+// Returns the opacity of the particle
+func (p *Particle) Alpha() float64 {
+	return math.Max(0.0, math.Min(1.0, p.emitter.Fade.At(p.Life())))
+}
+
+// Returns the scale of the particle
+func (p *Particle) Scale() float64 {
+	return math.Max(0.0, p.emitter.Scale.At(p.Life()))
+}
+
+// Pool of particles and the emitters feeding it. Particles over the capacity are not spawned
+type System struct {
+	particles []Particle
+	alive     int
+	emitters  []*Emitter
+	random    *rand.Rand
+}
+
+// Returns a system holding at most capacity particles
+func NewSystem(capacity int) *System {
+	return &System{
+		particles: make([]Particle, capacity),
+		alive:     0,
+		emitters:  nil,
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Returns how many particles the system can hold
+func (s *System) Capacity() int {
+	return len(s.particles)
+}
+
+// Returns how many particles are alive
+func (s *System) Len() int {
+	return s.alive
+}
+
+// Returns alive particles, valid until the next update
+func (s *System) Particles() []Particle {
+	return s.particles[:s.alive]
+}
+
+// Emits at the rate of the emitter until it is stopped
+func (s *System) Add(emitter *Emitter) {
+	s.emitters = append(s.emitters, emitter)
+}
+
+// Emits count particles at once, returns how many fit
+func (s *System) Burst(emitter *Emitter, count int) int {
+	spawned := 0
+	for ; spawned < count && s.alive < len(s.particles); spawned++ {
+		s.spawn(emitter)
+	}
+
+	return spawned
+}
+
+// Removes all particles and emitters
+func (s *System) Clear() {
+	s.alive = 0
+	for index := range s.emitters {
+		s.emitters[index] = nil
+	}
+	s.emitters = s.emitters[:0]
+}
+
+func (s *System) spawn(emitter *Emitter) {
+	angle := emitter.Direction + (s.random.Float64()-0.5)*emitter.Spread
+	speed := emitter.Speed + (s.random.Float64()*2.0-1.0)*emitter.SpeedSpread
+	offsetAngle := s.random.Float64() * 2.0 * math.Pi
+	offset := emitter.Radius * math.Sqrt(s.random.Float64())
+	lifetime := emitter.Lifetime
+	if emitter.LifetimeSpread > 0 {
+		lifetime += time.Duration(s.random.Int63n(int64(emitter.LifetimeSpread)*2+1)) - emitter.LifetimeSpread
+	}
+
+	particle := &s.particles[s.alive]
+	s.alive++
+	particle.Position = emitter.Position.Add(physics.V(math.Cos(offsetAngle), math.Sin(offsetAngle)).Scale(offset))
+	particle.Velocity = physics.V(math.Cos(angle), math.Sin(angle)).Scale(speed)
+	particle.Age = 0
+	particle.Lifetime = lifetime
+	particle.emitter = emitter
+	particle.Image = ""
+	if len(emitter.Images) > 0 {
+		particle.Image = emitter.Images[s.random.Intn(len(emitter.Images))]
+	}
+	particle.Color = color.RGBA{255, 255, 255, 255}
+	if len(emitter.Colors) > 0 {
+		particle.Color = emitter.Colors[s.random.Intn(len(emitter.Colors))]
+	}
+}
+
+// Emits, moves and ages particles by delta
+func (s *System) Update(delta time.Duration) {
+	seconds := delta.Seconds()
+
+	emitters := s.emitters[:0]
+	for _, emitter := range s.emitters {
+		if emitter.Stopped() {
+			continue
+		}
+
+		emitting := delta
+		if emitter.Duration > 0 && emitter.elapsed+delta > emitter.Duration {
+			emitting = emitter.Duration - emitter.elapsed
+		}
+		emitter.elapsed += delta
+		emitter.pending += emitter.Rate * emitting.Seconds()
+		count := int(emitter.pending)
+		emitter.pending -= float64(count)
+		s.Burst(emitter, count)
+
+		if !emitter.Stopped() {
+			emitters = append(emitters, emitter)
+		}
+	}
+	for index := len(emitters); index < len(s.emitters); index++ {
+		s.emitters[index] = nil
+	}
+	s.emitters = emitters
+
+	for index := 0; index < s.alive; {
+		particle := &s.particles[index]
+		particle.Age += delta
+		if particle.Age >= particle.Lifetime {
+			// Swap the dead one with the last alive
+			s.alive--
+			s.particles[index] = s.particles[s.alive]
+			s.particles[s.alive].emitter = nil
+			continue
+		}
+
+		particle.Velocity = particle.Velocity.Add(particle.emitter.Gravity.Scale(seconds))
+		particle.Position = particle.Position.Add(particle.Velocity.Scale(seconds))
+		index++
+	}
+}
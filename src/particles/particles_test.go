@@ -0,0 +1,195 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package particles
+
+import (
+	"Unbewohnte/capyclick/physics"
+	"Unbewohnte/capyclick/tween"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCurve(t *testing.T) {
+	curve := Curve{From: 1.0, To: 0.0, Ease: tween.Linear}
+	if curve.At(0.0) != 1.0 || curve.At(0.25) != 0.75 || curve.At(1.0) != 0.0 {
+		t.Errorf("linear curve is off")
+	}
+
+	if Constant(2.0).At(0.7) != 2.0 {
+		t.Errorf("constant curve changes")
+	}
+
+	if (Curve{From: 0.0, To: 4.0}).At(0.5) != 2.0 {
+		t.Errorf("curve without easing is not linear")
+	}
+}
+
+func TestBurstCap(t *testing.T) {
+	system := NewSystem(10)
+	emitter := &Emitter{Lifetime: time.Second}
+
+	if spawned := system.Burst(emitter, 6); spawned != 6 {
+		t.Errorf("spawned %d, want 6", spawned)
+	}
+	if spawned := system.Burst(emitter, 6); spawned != 4 {
+		t.Errorf("spawned %d over the cap, want 4", spawned)
+	}
+	if system.Len() != system.Capacity() {
+		t.Errorf("%d particles alive, want %d", system.Len(), system.Capacity())
+	}
+}
+
+func TestLifetime(t *testing.T) {
+	system := NewSystem(100)
+	short := &Emitter{Lifetime: time.Second}
+	long := &Emitter{Lifetime: 3 * time.Second}
+	system.Burst(short, 10)
+	system.Burst(long, 5)
+
+	system.Update(time.Second / 2)
+	if system.Len() != 15 {
+		t.Fatalf("%d particles alive, want 15", system.Len())
+	}
+
+	system.Update(time.Second)
+	if system.Len() != 5 {
+		t.Fatalf("%d particles alive, want 5", system.Len())
+	}
+	for _, particle := range system.Particles() {
+		if particle.Lifetime != long.Lifetime {
+			t.Errorf("short lived particle survived")
+		}
+	}
+
+	system.Update(2 * time.Second)
+	if system.Len() != 0 {
+		t.Errorf("%d particles outlived their lifetime", system.Len())
+	}
+}
+
+func TestMotion(t *testing.T) {
+	system := NewSystem(1)
+	system.Burst(&Emitter{
+		Position: physics.V(10.0, 10.0),
+		Lifetime: time.Minute,
+		Speed:    100.0,
+		Gravity:  physics.V(0.0, 50.0),
+	}, 1)
+
+	for i := 0; i < 100; i++ {
+		system.Update(time.Second / 100)
+	}
+
+	particle := system.Particles()[0]
+	if math.Abs(particle.Position.X-110.0) > 1e-6 {
+		t.Errorf("x = %v, want 110", particle.Position.X)
+	}
+	if math.Abs(particle.Velocity.Y-50.0) > 1e-6 || particle.Position.Y < 30.0 || particle.Position.Y > 40.0 {
+		t.Errorf("gravity did not pull the particle: %+v", particle)
+	}
+}
+
+func TestSpread(t *testing.T) {
+	system := NewSystem(500)
+	system.Burst(&Emitter{
+		Lifetime:    time.Second,
+		Speed:       100.0,
+		SpeedSpread: 20.0,
+		Direction:   -math.Pi / 2.0,
+		Spread:      math.Pi / 2.0,
+		Radius:      5.0,
+	}, 500)
+
+	for _, particle := range system.Particles() {
+		speed := particle.Velocity.Len()
+		if speed < 80.0-1e-9 || speed > 120.0+1e-9 {
+			t.Fatalf("speed %v out of spread", speed)
+		}
+		angle := math.Atan2(particle.Velocity.Y, particle.Velocity.X)
+		if angle < -3.0*math.Pi/4.0-1e-9 || angle > -math.Pi/4.0+1e-9 {
+			t.Fatalf("angle %v out of the cone", angle)
+		}
+		if particle.Position.Len() > 5.0+1e-9 {
+			t.Fatalf("spawned %v away from the emitter", particle.Position.Len())
+		}
+	}
+}
+
+func TestRate(t *testing.T) {
+	system := NewSystem(1000)
+	emitter := &Emitter{
+		Rate:     100.0,
+		Duration: time.Second,
+		Lifetime: time.Minute,
+	}
+	system.Add(emitter)
+
+	for i := 0; i < 60; i++ {
+		system.Update(time.Second / 40)
+	}
+
+	if system.Len() != 100 {
+		t.Errorf("emitted %d particles in its duration, want 100", system.Len())
+	}
+	if !emitter.Stopped() {
+		t.Errorf("emitter outlived its duration")
+	}
+
+	endless := &Emitter{Rate: 10.0, Lifetime: time.Minute}
+	system.Add(endless)
+	system.Update(time.Second)
+	endless.Stop()
+	system.Update(time.Second)
+	if system.Len() != 110 {
+		t.Errorf("%d particles alive, want 110", system.Len())
+	}
+}
+
+func TestCurves(t *testing.T) {
+	system := NewSystem(1)
+	system.Burst(&Emitter{
+		Lifetime: time.Second,
+		Fade:     Curve{From: 1.0, To: 0.0, Ease: tween.Linear},
+		Scale:    Curve{From: 1.0, To: 3.0, Ease: tween.Linear},
+	}, 1)
+	system.Update(time.Second / 4)
+
+	particle := system.Particles()[0]
+	if math.Abs(particle.Alpha()-0.75) > 1e-9 || math.Abs(particle.Scale()-1.5) > 1e-9 {
+		t.Errorf("alpha %v, scale %v", particle.Alpha(), particle.Scale())
+	}
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	system := NewSystem(5000)
+	emitter := &Emitter{
+		Lifetime:       time.Second,
+		LifetimeSpread: time.Second / 2,
+		Speed:          100.0,
+		Spread:         2.0 * math.Pi,
+		Gravity:        physics.V(0.0, 100.0),
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		system.Burst(emitter, 100)
+		system.Update(time.Second / 60)
+	}
+}
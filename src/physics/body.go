@@ -0,0 +1,127 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package physics
+
+type Shape uint8
+
+const (
+	CircleShape Shape = iota
+	BoxShape
+)
+
+// Shape of the body used to find collisions, always centered on the body's position
+type Collider struct {
+	Shape Shape
+	// Radius of the circle
+	Radius float64
+	// Half of the box width and height
+	HalfSize Vec2
+}
+
+func Circle(radius float64) Collider {
+	return Collider{
+		Shape:  CircleShape,
+		Radius: radius,
+	}
+}
+
+func Box(width float64, height float64) Collider {
+	return Collider{
+		Shape:    BoxShape,
+		HalfSize: Vec2{width / 2.0, height / 2.0},
+	}
+}
+
+type Body struct {
+	// Center of the body
+	Position Vec2
+	Velocity Vec2
+	// Bodies without mass are static: nothing moves them, but others collide with them
+	Mass float64
+	// How much speed is kept after a bounce in [0.0; 1.0]
+	Restitution float64
+	// How much sliding along surfaces is resisted, 0.0 is ice
+	Friction float64
+	Collider Collider
+	// Moved from outside (e.g. dragged by the player): not integrated,
+	// pushes other bodies away but is not pushed itself
+	Kinematic bool
+	// Forces applied until the next step
+	force Vec2
+}
+
+// Returns a body of given shape and mass resting at the origin
+func NewBody(collider Collider, mass float64) *Body {
+	return &Body{
+		Position:    Vec2{},
+		Velocity:    Vec2{},
+		Mass:        mass,
+		Restitution: 0.0,
+		Friction:    0.0,
+		Collider:    collider,
+		Kinematic:   false,
+		force:       Vec2{},
+	}
+}
+
+// Returns true if the body is moved by the world
+func (b *Body) Dynamic() bool {
+	return b.Mass > 0.0 && !b.Kinematic
+}
+
+// Returns 1/mass for dynamic bodies and 0 for the rest
+func (b *Body) InverseMass() float64 {
+	if !b.Dynamic() {
+		return 0.0
+	}
+
+	return 1.0 / b.Mass
+}
+
+// Adds a force that acts on the body during the next step
+func (b *Body) ApplyForce(force Vec2) {
+	b.force = b.force.Add(force)
+}
+
+// Changes body velocity right away
+func (b *Body) ApplyImpulse(impulse Vec2) {
+	b.Velocity = b.Velocity.Add(impulse.Scale(b.InverseMass()))
+}
+
+// Returns the smallest rectangle containing the collider
+func (b *Body) Bounds() Rect {
+	half := b.Collider.HalfSize
+	if b.Collider.Shape == CircleShape {
+		half = Vec2{b.Collider.Radius, b.Collider.Radius}
+	}
+
+	return Rect{
+		Min: b.Position.Sub(half),
+		Max: b.Position.Add(half),
+	}
+}
+
+// Returns true if the point is inside the collider
+func (b *Body) Contains(point Vec2) bool {
+	if b.Collider.Shape == CircleShape {
+		return point.Sub(b.Position).Len() <= b.Collider.Radius
+	}
+
+	return b.Bounds().Contains(point)
+}
@@ -0,0 +1,190 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package physics
+
+import "math"
+
+const (
+	// How much overlap is tolerated before bodies are pushed apart, keeps resting bodies from jittering
+	penetrationSlop float64 = 0.05
+	// Which part of the overlap is corrected every step
+	correctionPercent float64 = 0.8
+)
+
+// Two overlapping bodies
+type Contact struct {
+	A *Body
+	B *Body
+	// Direction from A to B along which they are separated
+	Normal Vec2
+	// How deep they overlap
+	Depth float64
+}
+
+// Returns 1.0 for non-negative values and -1.0 for negative ones
+func sign(value float64) float64 {
+	if value < 0.0 {
+		return -1.0
+	}
+	return 1.0
+}
+
+// Returns the contact between two bodies if they overlap
+func Collide(a *Body, b *Body) (Contact, bool) {
+	switch {
+	case a.Collider.Shape == CircleShape && b.Collider.Shape == CircleShape:
+		return collideCircles(a, b)
+
+	case a.Collider.Shape == BoxShape && b.Collider.Shape == BoxShape:
+		return collideBoxes(a, b)
+
+	case a.Collider.Shape == CircleShape:
+		return collideCircleBox(a, b)
+
+	default:
+		contact, ok := collideCircleBox(b, a)
+		if !ok {
+			return Contact{}, false
+		}
+		return Contact{A: a, B: b, Normal: contact.Normal.Scale(-1.0), Depth: contact.Depth}, true
+	}
+}
+
+func collideCircles(a *Body, b *Body) (Contact, bool) {
+	difference := b.Position.Sub(a.Position)
+	distance := difference.Len()
+	radii := a.Collider.Radius + b.Collider.Radius
+	if distance >= radii {
+		return Contact{}, false
+	}
+
+	normal := Vec2{0.0, 1.0}
+	if distance > 0.0 {
+		normal = difference.Scale(1.0 / distance)
+	}
+
+	return Contact{A: a, B: b, Normal: normal, Depth: radii - distance}, true
+}
+
+func collideBoxes(a *Body, b *Body) (Contact, bool) {
+	difference := b.Position.Sub(a.Position)
+	overlapX := a.Collider.HalfSize.X + b.Collider.HalfSize.X - math.Abs(difference.X)
+	if overlapX <= 0.0 {
+		return Contact{}, false
+	}
+
+	overlapY := a.Collider.HalfSize.Y + b.Collider.HalfSize.Y - math.Abs(difference.Y)
+	if overlapY <= 0.0 {
+		return Contact{}, false
+	}
+
+	// Separate along the axis of the least overlap
+	if overlapX < overlapY {
+		return Contact{A: a, B: b, Normal: Vec2{sign(difference.X), 0.0}, Depth: overlapX}, true
+	}
+	return Contact{A: a, B: b, Normal: Vec2{0.0, sign(difference.Y)}, Depth: overlapY}, true
+}
+
+func collideCircleBox(circle *Body, box *Body) (Contact, bool) {
+	bounds := box.Bounds()
+	radius := circle.Collider.Radius
+
+	// The closest point of the box to the circle's center
+	closest := Vec2{
+		math.Max(bounds.Min.X, math.Min(circle.Position.X, bounds.Max.X)),
+		math.Max(bounds.Min.Y, math.Min(circle.Position.Y, bounds.Max.Y)),
+	}
+
+	if closest != circle.Position {
+		difference := closest.Sub(circle.Position)
+		distance := difference.Len()
+		if distance >= radius {
+			return Contact{}, false
+		}
+
+		return Contact{A: circle, B: box, Normal: difference.Scale(1.0 / distance), Depth: radius - distance}, true
+	}
+
+	// The center is inside the box, get out the shortest way
+	local := circle.Position.Sub(box.Position)
+	outX := box.Collider.HalfSize.X - math.Abs(local.X)
+	outY := box.Collider.HalfSize.Y - math.Abs(local.Y)
+	if outX < outY {
+		return Contact{A: circle, B: box, Normal: Vec2{-sign(local.X), 0.0}, Depth: outX + radius}, true
+	}
+	return Contact{A: circle, B: box, Normal: Vec2{0.0, -sign(local.Y)}, Depth: outY + radius}, true
+}
+
+// Pushes overlapping bodies apart according to their masses
+func (c Contact) separate() {
+	inverseA := c.A.InverseMass()
+	inverseB := c.B.InverseMass()
+	inverseSum := inverseA + inverseB
+	if inverseSum == 0.0 {
+		return
+	}
+
+	correction := c.Normal.Scale(math.Max(c.Depth-penetrationSlop, 0.0) / inverseSum * correctionPercent)
+	c.A.Position = c.A.Position.Sub(correction.Scale(inverseA))
+	c.B.Position = c.B.Position.Add(correction.Scale(inverseB))
+}
+
+// Applies bounce and friction impulses. Bounces slower than resting speed are absorbed
+func (c Contact) resolve(restingSpeed float64) {
+	inverseA := c.A.InverseMass()
+	inverseB := c.B.InverseMass()
+	inverseSum := inverseA + inverseB
+	if inverseSum == 0.0 {
+		return
+	}
+
+	relative := c.B.Velocity.Sub(c.A.Velocity)
+	approaching := relative.Dot(c.Normal)
+	if approaching > 0.0 {
+		// Already moving apart
+		return
+	}
+
+	restitution := math.Max(c.A.Restitution, c.B.Restitution)
+	if -approaching < restingSpeed {
+		restitution = 0.0
+	}
+
+	magnitude := -(1.0 + restitution) * approaching / inverseSum
+	impulse := c.Normal.Scale(magnitude)
+	c.A.Velocity = c.A.Velocity.Sub(impulse.Scale(inverseA))
+	c.B.Velocity = c.B.Velocity.Add(impulse.Scale(inverseB))
+
+	// Friction works against sliding and can not be stronger than the bounce itself
+	relative = c.B.Velocity.Sub(c.A.Velocity)
+	tangent := relative.Sub(c.Normal.Scale(relative.Dot(c.Normal))).Normalized()
+	if tangent == (Vec2{}) {
+		return
+	}
+
+	friction := -relative.Dot(tangent) / inverseSum
+	limit := magnitude * math.Sqrt(c.A.Friction*c.B.Friction)
+	if math.Abs(friction) > limit {
+		friction = sign(friction) * limit
+	}
+
+	frictionImpulse := tangent.Scale(friction)
+	c.A.Velocity = c.A.Velocity.Sub(frictionImpulse.Scale(inverseA))
+	c.B.Velocity = c.B.Velocity.Add(frictionImpulse.Scale(inverseB))
+}
@@ -0,0 +1,367 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package physics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func near(a float64, b float64, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+// Advances the world frame by frame the way a game would
+func simulate(world *World, duration time.Duration) {
+	for frames := int(duration / DefaultStep); frames > 0; frames-- {
+		world.Advance(DefaultStep)
+	}
+}
+
+func TestCollide(t *testing.T) {
+	circle := func(x, y, radius float64) *Body {
+		body := NewBody(Circle(radius), 1.0)
+		body.Position = V(x, y)
+		return body
+	}
+	box := func(x, y, width, height float64) *Body {
+		body := NewBody(Box(width, height), 1.0)
+		body.Position = V(x, y)
+		return body
+	}
+
+	tests := []struct {
+		name    string
+		a       *Body
+		b       *Body
+		touches bool
+		normal  Vec2
+		depth   float64
+	}{
+		{"circles apart", circle(0, 0, 1), circle(3, 0, 1), false, Vec2{}, 0},
+		{"circles overlap", circle(0, 0, 1), circle(1.5, 0, 1), true, V(1, 0), 0.5},
+		{"circles on top of each other", circle(0, 0, 1), circle(0, 0, 1), true, V(0, 1), 2},
+		{"boxes apart", box(0, 0, 2, 2), box(0, 3, 2, 2), false, Vec2{}, 0},
+		{"boxes overlap vertically", box(0, 0, 2, 2), box(0.5, 1.5, 2, 2), true, V(0, 1), 0.5},
+		{"boxes overlap horizontally", box(0, 0, 2, 2), box(-1.75, 0.5, 2, 2), true, V(-1, 0), 0.25},
+		{"circle above box", circle(0, -1.5, 1), box(0, 0, 2, 2), true, V(0, 1), 0.5},
+		{"circle near box corner", circle(2, 2, 1), box(0, 0, 2, 2), false, Vec2{}, 0},
+		{"circle inside box", circle(0.5, 0, 0.25), box(0, 0, 2, 2), true, V(-1, 0), 0.75},
+		{"box below circle", box(0, 0, 2, 2), circle(0, -1.5, 1), true, V(0, -1), 0.5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contact, touches := Collide(test.a, test.b)
+			if touches != test.touches {
+				t.Fatalf("expected touching to be %v, got %v", test.touches, touches)
+			}
+			if !touches {
+				return
+			}
+
+			if contact.A != test.a || contact.B != test.b {
+				t.Errorf("contact bodies are swapped")
+			}
+			if !near(contact.Normal.X, test.normal.X, 1e-9) || !near(contact.Normal.Y, test.normal.Y, 1e-9) {
+				t.Errorf("expected normal %v, got %v", test.normal, contact.Normal)
+			}
+			if !near(contact.Depth, test.depth, 1e-9) {
+				t.Errorf("expected depth %f, got %f", test.depth, contact.Depth)
+			}
+		})
+	}
+}
+
+func TestFreeFall(t *testing.T) {
+	world := NewWorld(V(0, 10))
+	body := NewBody(Circle(1), 2.0)
+	world.Add(body)
+
+	simulate(world, time.Second)
+
+	// Semi-implicit Euler overshoots a little: g*t^2/2 * (1 + dt)
+	if !near(body.Position.Y, 5.0, 0.1) {
+		t.Errorf("expected to fall 5 units in a second, fell %f", body.Position.Y)
+	}
+	if !near(body.Velocity.Y, 10.0, 1e-6) {
+		t.Errorf("expected to reach speed of 10, got %f", body.Velocity.Y)
+	}
+}
+
+func TestMassDoesNotChangeFalling(t *testing.T) {
+	world := NewWorld(V(0, 10))
+	light := NewBody(Circle(1), 1.0)
+	heavy := NewBody(Circle(1), 100.0)
+	heavy.Position = V(10, 0)
+	world.Add(light, heavy)
+
+	simulate(world, time.Second)
+	if !near(light.Position.Y, heavy.Position.Y, 1e-9) {
+		t.Errorf("bodies of different mass fell differently: %f and %f", light.Position.Y, heavy.Position.Y)
+	}
+}
+
+func TestForces(t *testing.T) {
+	world := NewWorld(Vec2{})
+	light := NewBody(Circle(1), 1.0)
+	heavy := NewBody(Circle(1), 4.0)
+	heavy.Position = V(10, 0)
+	world.Add(light, heavy)
+
+	light.ApplyForce(V(120, 0))
+	heavy.ApplyForce(V(120, 0))
+	world.Step(DefaultStep.Seconds())
+
+	if !near(light.Velocity.X, heavy.Velocity.X*4.0, 1e-9) {
+		t.Errorf("heavier body did not accelerate slower: %f and %f", light.Velocity.X, heavy.Velocity.X)
+	}
+
+	// Forces last a single step
+	velocity := light.Velocity.X
+	world.Step(DefaultStep.Seconds())
+	if light.Velocity.X != velocity {
+		t.Errorf("force kept acting after the step")
+	}
+
+	heavy.ApplyImpulse(V(8, 0))
+	if !near(heavy.Velocity.X, velocity/4.0+2.0, 1e-9) {
+		t.Errorf("impulse did not respect mass, got velocity %f", heavy.Velocity.X)
+	}
+}
+
+func TestRestingOnTheFloor(t *testing.T) {
+	world := NewWorld(V(0, 300))
+	world.Bounds = RectAt(0, 0, 100, 100)
+	body := NewBody(Box(10, 10), 1.0)
+	body.Restitution = 0.5
+	body.Position = V(50, 20)
+	world.Add(body)
+
+	simulate(world, 5*time.Second)
+
+	if !near(body.Bounds().Max.Y, 100, 0.5) {
+		t.Errorf("expected to rest on the floor, bottom is at %f", body.Bounds().Max.Y)
+	}
+	if math.Abs(body.Velocity.Y) > 5.0 {
+		t.Errorf("expected to settle down, still moving at %f", body.Velocity.Y)
+	}
+}
+
+func TestBoundsKeepBodiesInside(t *testing.T) {
+	world := NewWorld(Vec2{})
+	world.Bounds = RectAt(0, 0, 100, 100)
+	body := NewBody(Circle(5), 1.0)
+	body.Restitution = 1.0
+	body.Position = V(50, 50)
+	body.Velocity = V(-400, 0)
+	world.Add(body)
+
+	for i := 0; i < 120; i++ {
+		world.Step(DefaultStep.Seconds())
+		bounds := body.Bounds()
+		if bounds.Min.X < -5.0 || bounds.Max.X > 105.0 {
+			t.Fatalf("body escaped the bounds: %v", bounds)
+		}
+	}
+
+	if !near(math.Abs(body.Velocity.X), 400.0, 1e-6) {
+		t.Errorf("perfectly elastic bounce lost speed, got %f", body.Velocity.X)
+	}
+}
+
+func TestElasticCollisionOfEqualMasses(t *testing.T) {
+	world := NewWorld(Vec2{})
+	a := NewBody(Circle(1), 1.0)
+	a.Restitution = 1.0
+	a.Position = V(0, 0)
+	a.Velocity = V(10, 0)
+	b := NewBody(Circle(1), 1.0)
+	b.Restitution = 1.0
+	b.Position = V(2.05, 0)
+	world.Add(a, b)
+
+	simulate(world, time.Second/10)
+
+	if !near(a.Velocity.X, 0.0, 1e-6) || !near(b.Velocity.X, 10.0, 1e-6) {
+		t.Errorf("expected velocities to swap, got %f and %f", a.Velocity.X, b.Velocity.X)
+	}
+}
+
+func TestMomentumIsConserved(t *testing.T) {
+	world := NewWorld(Vec2{})
+	light := NewBody(Box(2, 2), 1.0)
+	light.Position = V(0, 0)
+	light.Velocity = V(20, 0)
+	heavy := NewBody(Box(2, 2), 5.0)
+	heavy.Position = V(2.1, 0)
+	world.Add(light, heavy)
+
+	before := light.Velocity.Scale(light.Mass).Add(heavy.Velocity.Scale(heavy.Mass))
+	simulate(world, time.Second/10)
+	after := light.Velocity.Scale(light.Mass).Add(heavy.Velocity.Scale(heavy.Mass))
+
+	if !near(before.X, after.X, 1e-6) {
+		t.Errorf("momentum changed from %f to %f", before.X, after.X)
+	}
+	if heavy.Velocity.X <= 0.0 || heavy.Velocity.X >= 20.0 {
+		t.Errorf("heavy body was not pushed reasonably, moving at %f", heavy.Velocity.X)
+	}
+	if light.Velocity.X >= heavy.Velocity.X {
+		t.Errorf("bodies still move into each other: %f and %f", light.Velocity.X, heavy.Velocity.X)
+	}
+}
+
+func TestStaticBodiesDoNotMove(t *testing.T) {
+	world := NewWorld(V(0, 100))
+	ground := NewBody(Box(100, 10), 0.0)
+	ground.Position = V(0, 50)
+	ball := NewBody(Circle(2), 1.0)
+	ball.Position = V(0, 0)
+	world.Add(ground, ball)
+
+	simulate(world, 3*time.Second)
+
+	if ground.Position != V(0, 50) || ground.Velocity != (Vec2{}) {
+		t.Errorf("static body moved to %v", ground.Position)
+	}
+	if !near(ball.Position.Y, 43.0, 0.5) {
+		t.Errorf("expected the ball to rest on the ground, it is at %f", ball.Position.Y)
+	}
+}
+
+func TestFriction(t *testing.T) {
+	slide := func(friction float64) float64 {
+		world := NewWorld(V(0, 300))
+		world.Bounds = RectAt(0, 0, 10000, 100)
+		world.BoundsFriction = friction
+		body := NewBody(Box(10, 10), 1.0)
+		body.Friction = friction
+		body.Position = V(50, 95)
+		body.Velocity = V(200, 0)
+		world.Add(body)
+
+		simulate(world, time.Second/2)
+		return body.Velocity.X
+	}
+
+	slippery := slide(0.0)
+	rough := slide(0.5)
+
+	if !near(slippery, 200.0, 1e-6) {
+		t.Errorf("frictionless floor slowed the body down to %f", slippery)
+	}
+	// Coulomb friction decelerates by mu*g
+	if !near(rough, 200.0-0.5*300.0*0.5, 5.0) {
+		t.Errorf("expected friction to slow the body down to 125, got %f", rough)
+	}
+	if rough < 0.0 {
+		t.Errorf("friction reversed the body")
+	}
+}
+
+func TestKinematicBodies(t *testing.T) {
+	world := NewWorld(V(0, 100))
+	paddle := NewBody(Box(10, 2), 1.0)
+	paddle.Kinematic = true
+	paddle.Position = V(0, 10)
+	paddle.Velocity = V(0, -50)
+	ball := NewBody(Circle(1), 1.0)
+	ball.Position = V(0, 8.5)
+	world.Add(paddle, ball)
+
+	world.Step(DefaultStep.Seconds())
+
+	if paddle.Position != V(0, 10) {
+		t.Errorf("kinematic body was moved by the world to %v", paddle.Position)
+	}
+	if ball.Velocity.Y >= -50.0+1e-6 {
+		t.Errorf("kinematic body did not push the ball, its velocity is %f", ball.Velocity.Y)
+	}
+}
+
+func TestOnContact(t *testing.T) {
+	world := NewWorld(Vec2{})
+	static := NewBody(Box(10, 10), 0.0)
+	kinematic := NewBody(Circle(2), 1.0)
+	kinematic.Kinematic = true
+	kinematic.Position = V(6, 0)
+	lonely := NewBody(Circle(1), 1.0)
+	lonely.Position = V(100, 100)
+	world.Add(static, kinematic, lonely)
+
+	var contacts []Contact
+	world.OnContact = func(contact Contact) {
+		contacts = append(contacts, contact)
+	}
+	world.Step(DefaultStep.Seconds())
+
+	if len(contacts) != 1 {
+		t.Fatalf("expected a single contact, got %d", len(contacts))
+	}
+	if contacts[0].A != static || contacts[0].B != kinematic {
+		t.Errorf("wrong bodies in contact")
+	}
+}
+
+func TestAddRemoveAndBodyAt(t *testing.T) {
+	world := NewWorld(Vec2{})
+	bottom := NewBody(Box(10, 10), 1.0)
+	top := NewBody(Circle(2), 1.0)
+	world.Add(bottom, top)
+
+	if world.BodyAt(V(1, 1)) != top {
+		t.Errorf("expected the last added body to be picked")
+	}
+	if world.BodyAt(V(4, 4)) != bottom {
+		t.Errorf("expected the box to be picked outside of the circle")
+	}
+	if world.BodyAt(V(50, 50)) != nil {
+		t.Errorf("picked a body in empty space")
+	}
+
+	world.Remove(top)
+	if len(world.Bodies()) != 1 || world.Bodies()[0] != bottom {
+		t.Errorf("failed to remove a body")
+	}
+	world.Remove(top)
+	if len(world.Bodies()) != 1 {
+		t.Errorf("removing a missing body changed the world")
+	}
+}
+
+func TestAdvanceIsFixedStep(t *testing.T) {
+	run := func(frame time.Duration, frames int) Vec2 {
+		world := NewWorld(V(0, 50))
+		body := NewBody(Circle(1), 1.0)
+		body.Velocity = V(3, 0)
+		world.Add(body)
+		for i := 0; i < frames; i++ {
+			world.Advance(frame)
+		}
+		return body.Position
+	}
+
+	fast := run(DefaultStep, 120)
+	slow := run(DefaultStep*4, 30)
+	if !near(fast.X, slow.X, 1e-9) || !near(fast.Y, slow.Y, 1e-9) {
+		t.Errorf("frame rate changed the outcome: %v and %v", fast, slow)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package physics
+
+import "math"
+
+// 2D vector, also used for points
+type Vec2 struct {
+	X float64
+	Y float64
+}
+
+func V(x float64, y float64) Vec2 {
+	return Vec2{X: x, Y: y}
+}
+
+func (v Vec2) Add(other Vec2) Vec2 {
+	return Vec2{v.X + other.X, v.Y + other.Y}
+}
+
+func (v Vec2) Sub(other Vec2) Vec2 {
+	return Vec2{v.X - other.X, v.Y - other.Y}
+}
+
+func (v Vec2) Scale(factor float64) Vec2 {
+	return Vec2{v.X * factor, v.Y * factor}
+}
+
+func (v Vec2) Dot(other Vec2) float64 {
+	return v.X*other.X + v.Y*other.Y
+}
+
+func (v Vec2) Len() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+// Returns a vector of the same direction and length of 1, zero vector stays zero
+func (v Vec2) Normalized() Vec2 {
+	length := v.Len()
+	if length == 0.0 {
+		return Vec2{}
+	}
+
+	return v.Scale(1.0 / length)
+}
+
+// Axis-aligned rectangle
+type Rect struct {
+	Min Vec2
+	Max Vec2
+}
+
+// Returns a rectangle with given top left corner and size
+func RectAt(x float64, y float64, width float64, height float64) Rect {
+	return Rect{
+		Min: Vec2{x, y},
+		Max: Vec2{x + width, y + height},
+	}
+}
+
+func (r Rect) Width() float64 {
+	return r.Max.X - r.Min.X
+}
+
+func (r Rect) Height() float64 {
+	return r.Max.Y - r.Min.Y
+}
+
+// Returns true if the rectangle has no area
+func (r Rect) Empty() bool {
+	return r.Width() <= 0.0 || r.Height() <= 0.0
+}
+
+func (r Rect) Contains(point Vec2) bool {
+	return point.X >= r.Min.X && point.X <= r.Max.X &&
+		point.Y >= r.Min.Y && point.Y <= r.Max.Y
+}
+
+func (r Rect) Center() Vec2 {
+	return Vec2{(r.Min.X + r.Max.X) / 2.0, (r.Min.Y + r.Max.Y) / 2.0}
+}
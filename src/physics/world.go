@@ -0,0 +1,188 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package physics
+
+import (
+	"Unbewohnte/capyclick/timestep"
+	"time"
+)
+
+const (
+	// How long a single world step is
+	DefaultStep time.Duration = time.Second / 120
+	// The most steps a single Advance call runs
+	maxStepsPerAdvance int = 16
+	// How many times contacts are resolved in a step, more is stabler for stacks
+	defaultIterations int = 4
+)
+
+// Collection of bodies moved by gravity and bouncing off each other
+type World struct {
+	Gravity Vec2
+	// Dynamic bodies are kept inside, an empty rectangle leaves the world unbounded
+	Bounds Rect
+	// Friction of the bounds' walls
+	BoundsFriction float64
+	// Bounces slower than this are absorbed so that resting bodies stay at rest
+	RestingSpeed float64
+	Iterations   int
+	// Called for every pair of touching bodies after a step
+	OnContact func(contact Contact)
+	bodies    []*Body
+	clock     timestep.Accumulator
+}
+
+// Returns an empty unbounded world
+func NewWorld(gravity Vec2) *World {
+	return &World{
+		Gravity:        gravity,
+		Bounds:         Rect{},
+		BoundsFriction: 0.5,
+		RestingSpeed:   gravity.Len() * DefaultStep.Seconds() * 2.0,
+		Iterations:     defaultIterations,
+		OnContact:      nil,
+		bodies:         nil,
+		clock:          timestep.New(DefaultStep, maxStepsPerAdvance),
+	}
+}
+
+func (w *World) Add(bodies ...*Body) {
+	w.bodies = append(w.bodies, bodies...)
+}
+
+func (w *World) Remove(body *Body) {
+	for index, existing := range w.bodies {
+		if existing == body {
+			w.bodies = append(w.bodies[:index], w.bodies[index+1:]...)
+			return
+		}
+	}
+}
+
+// Returns bodies in the order they were added
+func (w *World) Bodies() []*Body {
+	return w.bodies
+}
+
+// Returns the last added body containing the point or nil
+func (w *World) BodyAt(point Vec2) *Body {
+	for index := len(w.bodies) - 1; index >= 0; index-- {
+		if w.bodies[index].Contains(point) {
+			return w.bodies[index]
+		}
+	}
+
+	return nil
+}
+
+// Moves the world on by elapsed time in fixed steps. Returns how many steps were run
+func (w *World) Advance(elapsed time.Duration) int {
+	steps := w.clock.Advance(elapsed)
+	for i := 0; i < steps; i++ {
+		w.Step(w.clock.Step.Seconds())
+	}
+
+	return steps
+}
+
+// Returns true if the body can touch others on its own
+func moving(body *Body) bool {
+	return body.Dynamic() || body.Kinematic
+}
+
+// Returns contacts of the body with the bounds' walls
+func (w *World) boundsContacts(body *Body, contacts []Contact) []Contact {
+	if w.Bounds.Empty() || !body.Dynamic() {
+		return contacts
+	}
+
+	wall := &Body{Mass: 0.0, Friction: w.BoundsFriction}
+	bounds := body.Bounds()
+	if depth := w.Bounds.Min.X - bounds.Min.X; depth > 0.0 {
+		contacts = append(contacts, Contact{A: body, B: wall, Normal: Vec2{-1.0, 0.0}, Depth: depth})
+	}
+	if depth := bounds.Max.X - w.Bounds.Max.X; depth > 0.0 {
+		contacts = append(contacts, Contact{A: body, B: wall, Normal: Vec2{1.0, 0.0}, Depth: depth})
+	}
+	if depth := w.Bounds.Min.Y - bounds.Min.Y; depth > 0.0 {
+		contacts = append(contacts, Contact{A: body, B: wall, Normal: Vec2{0.0, -1.0}, Depth: depth})
+	}
+	if depth := bounds.Max.Y - w.Bounds.Max.Y; depth > 0.0 {
+		contacts = append(contacts, Contact{A: body, B: wall, Normal: Vec2{0.0, 1.0}, Depth: depth})
+	}
+
+	return contacts
+}
+
+// Moves the world on by dt seconds
+func (w *World) Step(dt float64) {
+	// Integrate
+	for _, body := range w.bodies {
+		if !body.Dynamic() {
+			body.force = Vec2{}
+			continue
+		}
+
+		acceleration := w.Gravity.Add(body.force.Scale(body.InverseMass()))
+		body.Velocity = body.Velocity.Add(acceleration.Scale(dt))
+		body.Position = body.Position.Add(body.Velocity.Scale(dt))
+		body.force = Vec2{}
+	}
+
+	// Find what touches what
+	var touching []Contact
+	for i := 0; i < len(w.bodies); i++ {
+		for j := i + 1; j < len(w.bodies); j++ {
+			a, b := w.bodies[i], w.bodies[j]
+			if !moving(a) && !moving(b) {
+				continue
+			}
+
+			contact, ok := Collide(a, b)
+			if ok {
+				touching = append(touching, contact)
+			}
+		}
+	}
+
+	contacts := append([]Contact(nil), touching...)
+	for _, body := range w.bodies {
+		contacts = w.boundsContacts(body, contacts)
+	}
+
+	// Resolve
+	iterations := w.Iterations
+	if iterations < 1 {
+		iterations = 1
+	}
+	for i := 0; i < iterations; i++ {
+		for _, contact := range contacts {
+			contact.resolve(w.RestingSpeed)
+		}
+	}
+	for _, contact := range contacts {
+		contact.separate()
+	}
+
+	if w.OnContact != nil {
+		for _, contact := range touching {
+			w.OnContact(contact)
+		}
+	}
+}
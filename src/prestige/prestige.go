@@ -0,0 +1,79 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package prestige
+
+import (
+	"Unbewohnte/capyclick/bignum"
+	"Unbewohnte/capyclick/save"
+	"errors"
+)
+
+const (
+	// Minimal level required to be reborn
+	MinLevel uint32 = 10
+	// How many lifetime points are needed for a single golden mandarin (grows quadratically)
+	PointsPerMandarin uint64 = 10000
+	// How much each golden mandarin adds to the click and passive income multiplier
+	MultiplierPerMandarin float64 = 0.1
+)
+
+var ErrCannotPrestige error = errors.New("not eligible for rebirth")
+
+// Returns how many golden mandarins the save has earned over its lifetime
+func earnedMandarins(s save.Save) bignum.Number {
+	return s.LifetimePoints.DivUint(PointsPerMandarin).Sqrt()
+}
+
+// Returns how many golden mandarins a rebirth would grant right now
+func Available(s save.Save) bignum.Number {
+	return earnedMandarins(s).Sub(s.GoldenMandarins)
+}
+
+// Returns true if the save is eligible for rebirth
+func CanPrestige(s save.Save) bool {
+	return s.Level >= MinLevel && !Available(s).IsZero()
+}
+
+// Returns a permanent multiplier granted by given amount of golden mandarins
+func MultiplierFor(goldenMandarins bignum.Number) float64 {
+	return 1.0 + goldenMandarins.Float64()*MultiplierPerMandarin
+}
+
+// Returns a permanent multiplier granted by golden mandarins
+func Multiplier(s save.Save) float64 {
+	return MultiplierFor(s.GoldenMandarins)
+}
+
+// Converts lifetime points into golden mandarins and starts a new run.
+// Returns how many golden mandarins were gained
+func Reset(s *save.Save) (bignum.Number, error) {
+	if !CanPrestige(*s) {
+		return bignum.Number{}, ErrCannotPrestige
+	}
+
+	gained := Available(*s)
+	s.GoldenMandarins = s.GoldenMandarins.Add(gained)
+	s.Prestiges++
+
+	s.Points = bignum.Number{}
+	s.Level = 1
+	s.PassiveIncome = bignum.Number{}
+
+	return gained, nil
+}
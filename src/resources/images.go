@@ -0,0 +1,127 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package resources
+
+import (
+	"Unbewohnte/capyclick/atlas"
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+
+	_ "image/png"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// How wide the packed texture can get
+const AtlasWidth int = 1024
+
+var ErrNoImage error = errors.New("no such image")
+
+type region struct {
+	filename string
+	rect     image.Rectangle
+}
+
+// Images ready for drawing, every file is decoded only once
+var images = struct {
+	sync.Mutex
+	byName   map[string]*ebiten.Image
+	byRegion map[region]*ebiten.Image
+}{
+	byName:   make(map[string]*ebiten.Image),
+	byRegion: make(map[region]*ebiten.Image),
+}
+
+// Returns the image with given file name ready for drawing, nil if there is no such image.
+// The same image is shared by every caller, so it must never be drawn onto
+func Image(filename string) *ebiten.Image {
+	images.Lock()
+	defer images.Unlock()
+
+	if img, ok := images.byName[filename]; ok {
+		return img
+	}
+
+	decoded := ImageFromFile(filename)
+	if decoded == nil {
+		return nil
+	}
+
+	img := ebiten.NewImageFromImage(decoded)
+	images.byName[filename] = img
+	return img
+}
+
+// Returns a part of the image ready for drawing, e.g. a frame of a sprite sheet.
+// Parts are cached the same way whole images are, nil if there is no such image
+func SubImage(filename string, rect image.Rectangle) *ebiten.Image {
+	whole := Image(filename)
+	if whole == nil {
+		return nil
+	}
+
+	images.Lock()
+	defer images.Unlock()
+
+	key := region{filename: filename, rect: rect}
+	if img, ok := images.byRegion[key]; ok {
+		return img
+	}
+
+	// Parts of an atlas region are relative to the image itself
+	img := whole.SubImage(rect.Add(whole.Bounds().Min)).(*ebiten.Image)
+	images.byRegion[key] = img
+	return img
+}
+
+// Packs given images into a single texture, later Image calls return their parts of it.
+// Images handed out before stay valid
+func PackAtlas(filenames ...string) error {
+	decoded := make(map[string]image.Image, len(filenames))
+	for _, filename := range filenames {
+		img := ImageFromFile(filename)
+		if img == nil {
+			return fmt.Errorf("%w: %s", ErrNoImage, filename)
+		}
+		decoded[filename] = img
+	}
+
+	packed, err := atlas.Pack(decoded, AtlasWidth, 1)
+	if err != nil {
+		return err
+	}
+	texture := ebiten.NewImageFromImage(packed.Image)
+
+	images.Lock()
+	defer images.Unlock()
+	for key := range images.byRegion {
+		if _, ok := packed.Region(key.filename); ok {
+			// Taken from the old texture
+			delete(images.byRegion, key)
+		}
+	}
+	for _, filename := range packed.Names() {
+		region, _ := packed.Region(filename)
+		images.byName[filename] = texture.SubImage(region).(*ebiten.Image)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package resources
+
+import (
+	"errors"
+	"image"
+	"testing"
+)
+
+func TestImageIsCached(t *testing.T) {
+	first := Image("mandarin_orange.png")
+	if first == nil {
+		t.Fatalf("failed to load an embedded image")
+	}
+
+	if Image("mandarin_orange.png") != first {
+		t.Errorf("image was loaded twice")
+	}
+
+	if Image("no_such_image.png") != nil {
+		t.Errorf("got an image that does not exist")
+	}
+}
+
+func TestCachedImageDoesNotAllocate(t *testing.T) {
+	Image("capybara_1.png")
+
+	allocations := testing.AllocsPerRun(100, func() {
+		Image("capybara_1.png")
+	})
+	if allocations != 0 {
+		t.Errorf("expected no allocations for a cached image, got %.1f", allocations)
+	}
+}
+
+func TestSubImage(t *testing.T) {
+	rect := image.Rect(16, 0, 32, 16)
+	frame := SubImage("capybara_1_sheet.png", rect)
+	if frame == nil {
+		t.Fatalf("failed to get a part of a sheet")
+	}
+	if frame.Bounds().Size() != rect.Size() {
+		t.Errorf("expected size %v, got %v", rect.Size(), frame.Bounds().Size())
+	}
+	if SubImage("capybara_1_sheet.png", rect) != frame {
+		t.Errorf("part of the image was not cached")
+	}
+	if SubImage("no_such_image.png", rect) != nil {
+		t.Errorf("got a part of an image that does not exist")
+	}
+}
+
+func TestPackAtlas(t *testing.T) {
+	names := []string{"mandarin_box_empty.png", "mandarin_box_not_empty.png", "mandarin_box_full.png"}
+	err := PackAtlas(names...)
+	if err != nil {
+		t.Fatalf("failed to pack an atlas: %s", err)
+	}
+
+	for _, name := range names {
+		img := Image(name)
+		if img == nil {
+			t.Fatalf("%s is missing after packing", name)
+		}
+		if img.Bounds().Size() != ImageFromFile(name).Bounds().Size() {
+			t.Errorf("%s has size %v in the atlas", name, img.Bounds().Size())
+		}
+	}
+
+	err = PackAtlas("no_such_image.png")
+	if !errors.Is(err, ErrNoImage) {
+		t.Errorf("expected ErrNoImage, got %v", err)
+	}
+}
+
+// What changing a sprite's image used to cost every frame
+func BenchmarkImageFromFile(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ImageFromFile("capybara_1.png")
+	}
+}
+
+func BenchmarkImage(b *testing.B) {
+	Image("capybara_1.png")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Image("capybara_1.png")
+	}
+}
@@ -0,0 +1,139 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package save
+
+import (
+	"Unbewohnte/capyclick/logger"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Key saves are signed with. It ships with the game, so signatures only make
+// casual editing evident. Release builds may set their own with
+// -ldflags "-X Unbewohnte/capyclick/save.signingKey=..."
+var signingKey string = "capyclick save signature"
+
+// How a save file is laid out on disk
+type envelope struct {
+	SaveVersion uint8 `json:"saveVersion"`
+	// Hex SHA-256 of compact data
+	Hash string `json:"hash,omitempty"`
+	// Hex HMAC-SHA-256 of compact data
+	Signature string          `json:"signature,omitempty"`
+	Data      json.RawMessage `json:"data"`
+	// Set by decode for saves upgraded from versions that were not signed, never read from disk
+	legacy bool
+}
+
+// Returned by Load along with the save when its contents do not match the signature
+type TamperedError struct {
+	Key    string
+	Reason string
+}
+
+func (e *TamperedError) Error() string {
+	return fmt.Sprintf("save \"%s\" was modified outside the game: %s", e.Key, e.Reason)
+}
+
+func hashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func signatureOf(data []byte) string {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Wraps the save into a signed envelope
+func seal(save Save) (envelope, error) {
+	data, err := json.Marshal(save)
+	if err != nil {
+		return envelope{}, err
+	}
+
+	return envelope{
+		SaveVersion: CurrentVersion,
+		Hash:        hashOf(data),
+		Signature:   signatureOf(data),
+		Data:        data,
+	}, nil
+}
+
+// Returns a description of what is wrong with the envelope or an empty string if nothing is
+func (e envelope) verify() string {
+	if e.legacy {
+		// Older saves had nothing to check against, anyone could have written them
+		return "unsigned legacy save"
+	}
+
+	if e.Hash == "" || e.Signature == "" {
+		return "missing signature"
+	}
+
+	// Indentation is not a part of the signed data
+	var compact bytes.Buffer
+	err := json.Compact(&compact, e.Data)
+	if err != nil {
+		return "malformed data"
+	}
+
+	if e.Hash != hashOf(compact.Bytes()) {
+		return "content hash does not match"
+	}
+
+	if !hmac.Equal([]byte(e.Signature), []byte(signatureOf(compact.Bytes()))) {
+		return "signature does not match"
+	}
+
+	return ""
+}
+
+// What to do with a save that was modified outside the game
+type TamperPolicy string
+
+const (
+	// Keep the save as is, only complain in logs
+	TamperWarn TamperPolicy = "warn"
+	// Start over with a blank save
+	TamperReset TamperPolicy = "reset"
+	// Keep the save but mark it as modified so it is not ranked
+	TamperMark TamperPolicy = "mark"
+)
+
+// Decides the fate of a tampered save according to the policy. Unknown policies mark the save
+func HandleTampered(save Save, policy TamperPolicy) Save {
+	switch policy {
+	case TamperWarn:
+		logger.Warning("[Save] Keeping modified save as is")
+		return save
+	case TamperReset:
+		logger.Warning("[Save] Modified save was reset")
+		return Default()
+	default:
+		logger.Warning("[Save] Modified save is marked and will not be ranked")
+		save.Modified = true
+		return save
+	}
+}
@@ -0,0 +1,88 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package save
+
+import (
+	"Unbewohnte/capyclick/bignum"
+	"Unbewohnte/capyclick/migration"
+	"encoding/json"
+)
+
+// Upgrades older saves to the current version
+var migrations = migration.Registry{
+	Name:         "save",
+	VersionField: "saveVersion",
+	Current:      CurrentVersion,
+	Steps: map[uint8]migration.Step{
+		1: migrateV1,
+		2: migrateV2,
+		3: migrateV3,
+	},
+}
+
+// Lifetime points were not tracked, current points are the best guess
+func migrateV1(fields map[string]json.RawMessage) error {
+	if _, ok := fields["lifetimePoints"]; !ok {
+		if points, ok := fields["points"]; ok {
+			fields["lifetimePoints"] = points
+		}
+	}
+
+	return nil
+}
+
+// Numbers became arbitrary-precision and are stored as strings
+func migrateV2(fields map[string]json.RawMessage) error {
+	for _, name := range []string{"points", "passiveIncome", "lifetimePoints", "goldenMandarins"} {
+		raw, ok := fields[name]
+		if !ok {
+			continue
+		}
+
+		var number bignum.Number
+		err := json.Unmarshal(raw, &number)
+		if err != nil {
+			return err
+		}
+
+		fields[name], err = json.Marshal(number)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Saves became signed envelopes, fields moved into data. There is no
+// signature to check old fields against, such saves are verified as tampered
+func migrateV3(fields map[string]json.RawMessage) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	for name := range fields {
+		delete(fields, name)
+	}
+
+	fields["data"] = data
+
+	return nil
+}
@@ -0,0 +1,303 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package save
+
+import (
+	"Unbewohnte/capyclick/storage"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Key of the index that lists all slots
+const profilesIndexKey string = "profiles.json"
+
+// Name of the save inside a slot
+const slotSaveName string = "save.json"
+
+// Longest allowed profile name in characters
+const MaxProfileNameLength int = 24
+
+var (
+	ErrUnknownSlot error = errors.New("unknown save slot")
+	ErrInvalidName error = errors.New("profile name must be 1 to 24 characters long")
+)
+
+// What is known about a save slot without loading the save itself
+type Slot struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Level           uint32 `json:"level"`
+	PlaytimeSeconds uint64 `json:"playtimeSeconds"`
+	LastPlayedUnix  uint64 `json:"lastPlayedUnix"`
+}
+
+// Several saves kept in one store along with an index of their metadata.
+// Safe to use from several goroutines
+type Profiles struct {
+	mutex sync.Mutex
+	store storage.Store
+	// Slot that was played last
+	Current string `json:"current"`
+	NextID  uint64 `json:"nextId"`
+	Slots   []Slot `json:"slots"`
+}
+
+// Opens profiles kept in the store
+func OpenProfiles(store storage.Store) (*Profiles, error) {
+	profiles := &Profiles{
+		store:   store,
+		Current: "",
+		NextID:  1,
+		Slots:   nil,
+	}
+
+	_, err := storage.LoadWithBackup(store, profilesIndexKey, func(data []byte) error {
+		return json.Unmarshal(data, profiles)
+	})
+	if storage.IsNotExist(err) {
+		// Saves may outlive a lost index
+		err = profiles.recover()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// Rebuilds the index from saves found in the store
+func (p *Profiles) recover() error {
+	keys, err := p.store.List()
+	if err != nil {
+		return err
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !strings.HasSuffix(key, "/"+slotSaveName) {
+			continue
+		}
+		id := strings.TrimSuffix(key, "/"+slotSaveName)
+
+		slot := Slot{ID: id, Name: id}
+		loaded, err := Load(p.store, key)
+		if loaded != nil {
+			slot.Level = loaded.Level
+			slot.PlaytimeSeconds = loaded.PlaytimeSeconds
+			slot.LastPlayedUnix = loaded.LastSavedUnix
+		} else if err != nil {
+			continue
+		}
+		p.Slots = append(p.Slots, slot)
+
+		var number uint64
+		if _, err := fmt.Sscanf(id, "slot%d", &number); err == nil && number >= p.NextID {
+			p.NextID = number + 1
+		}
+	}
+
+	if len(p.Slots) == 0 {
+		return nil
+	}
+
+	return p.writeIndex()
+}
+
+// Writes the index of slots
+func (p *Profiles) writeIndex() error {
+	indexBytes, err := json.MarshalIndent(p, "", " ")
+	if err != nil {
+		return err
+	}
+
+	return p.store.Save(profilesIndexKey, indexBytes)
+}
+
+// Returns the key the save of the slot is kept under, every slot gets its own directory
+func slotKey(id string) string {
+	return id + "/" + slotSaveName
+}
+
+// Returns a copy of all slots
+func (p *Profiles) List() []Slot {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]Slot(nil), p.Slots...)
+}
+
+// Returns the slot with given ID
+func (p *Profiles) Get(id string) (Slot, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	index := p.indexOf(id)
+	if index < 0 {
+		return Slot{}, false
+	}
+
+	return p.Slots[index], true
+}
+
+func (p *Profiles) indexOf(id string) int {
+	for index, slot := range p.Slots {
+		if slot.ID == id {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// Trims the name and checks its length
+func cleanName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	length := utf8.RuneCountInString(name)
+	if length == 0 || length > MaxProfileNameLength {
+		return "", ErrInvalidName
+	}
+
+	return name, nil
+}
+
+// Creates a new slot with the save in it
+func (p *Profiles) Create(name string, save Save) (Slot, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	name, err := cleanName(name)
+	if err != nil {
+		return Slot{}, err
+	}
+
+	slot := Slot{
+		ID:   fmt.Sprintf("slot%d", p.NextID),
+		Name: name,
+	}
+	p.NextID++
+	p.Slots = append(p.Slots, slot)
+
+	err = p.write(slot.ID, save)
+	if err != nil {
+		p.Slots = p.Slots[:len(p.Slots)-1]
+		return Slot{}, err
+	}
+
+	return p.Slots[len(p.Slots)-1], nil
+}
+
+// Gives the slot a new name
+func (p *Profiles) Rename(id string, name string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	index := p.indexOf(id)
+	if index < 0 {
+		return ErrUnknownSlot
+	}
+
+	name, err := cleanName(name)
+	if err != nil {
+		return err
+	}
+
+	p.Slots[index].Name = name
+	return p.writeIndex()
+}
+
+// Removes the slot along with its save
+func (p *Profiles) Delete(id string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	index := p.indexOf(id)
+	if index < 0 {
+		return ErrUnknownSlot
+	}
+
+	p.Slots = append(p.Slots[:index], p.Slots[index+1:]...)
+	if p.Current == id {
+		p.Current = ""
+	}
+
+	err := p.writeIndex()
+	if err != nil {
+		return err
+	}
+
+	return p.store.Delete(slotKey(id))
+}
+
+// Remembers the slot as the one being played
+func (p *Profiles) Switch(id string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	index := p.indexOf(id)
+	if index < 0 {
+		return ErrUnknownSlot
+	}
+
+	p.Current = id
+	p.Slots[index].LastPlayedUnix = uint64(time.Now().Unix())
+	return p.writeIndex()
+}
+
+// Loads the save of the slot, see Load
+func (p *Profiles) Load(id string) (*Save, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.indexOf(id) < 0 {
+		return nil, ErrUnknownSlot
+	}
+
+	return Load(p.store, slotKey(id))
+}
+
+// Writes the save into the slot and updates its metadata
+func (p *Profiles) Store(id string, save Save) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.write(id, save)
+}
+
+func (p *Profiles) write(id string, save Save) error {
+	index := p.indexOf(id)
+	if index < 0 {
+		return ErrUnknownSlot
+	}
+
+	err := Write(p.store, slotKey(id), save)
+	if err != nil {
+		return err
+	}
+
+	p.Slots[index].Level = save.Level
+	p.Slots[index].PlaytimeSeconds = save.PlaytimeSeconds
+	p.Slots[index].LastPlayedUnix = save.LastSavedUnix
+	return p.writeIndex()
+}
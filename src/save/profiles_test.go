@@ -0,0 +1,159 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package save
+
+import (
+	"Unbewohnte/capyclick/storage"
+	"errors"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testProfiles(t, storage.NewMemory())
+	})
+	t.Run("profile directories", func(t *testing.T) {
+		testProfiles(t, storage.NewProfileDirs(t.TempDir()))
+	})
+}
+
+func testProfiles(t *testing.T, store storage.Store) {
+	profiles, err := OpenProfiles(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(profiles.List()) != 0 {
+		t.Fatalf("new profiles are not empty: %+v", profiles.List())
+	}
+
+	first, err := profiles.Create("Alice", Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress := Default()
+	progress.Level = 9
+	progress.PlaytimeSeconds = 3600
+	second, err := profiles.Create("  Bob  ", progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Name != "Bob" || second.Level != 9 || second.PlaytimeSeconds != 3600 {
+		t.Errorf("unexpected slot metadata: %+v", second)
+	}
+
+	_, err = profiles.Create("   ", Default())
+	if !errors.Is(err, ErrInvalidName) {
+		t.Errorf("expected an invalid name error, got %v", err)
+	}
+
+	err = profiles.Rename(first.ID, "Carol")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = profiles.Switch(second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Everything must survive reopening
+	reopened, err := OpenProfiles(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reopened.Current != second.ID {
+		t.Errorf("current slot is %q, expected %q", reopened.Current, second.ID)
+	}
+
+	slots := reopened.List()
+	if len(slots) != 2 || slots[0].Name != "Carol" || slots[1].Level != 9 {
+		t.Fatalf("unexpected slots after reopening: %+v", slots)
+	}
+
+	loaded, err := reopened.Load(second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Level != 9 {
+		t.Errorf("loaded level is %d, expected 9", loaded.Level)
+	}
+
+	err = reopened.Delete(second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Current != "" || len(reopened.List()) != 1 {
+		t.Errorf("slot was not deleted: %+v", reopened)
+	}
+
+	_, err = reopened.Load(second.ID)
+	if !errors.Is(err, ErrUnknownSlot) {
+		t.Errorf("expected an unknown slot error, got %v", err)
+	}
+
+	// IDs are never reused
+	third, err := reopened.Create("Dave", Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.ID == first.ID || third.ID == second.ID {
+		t.Errorf("slot ID %q was reused", third.ID)
+	}
+}
+
+func TestProfilesRecoverLostIndex(t *testing.T) {
+	store := storage.NewMemory()
+	profiles, err := OpenProfiles(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress := Default()
+	progress.Level = 4
+	slot, err := profiles.Create("Alice", progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Delete(profilesIndexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recovered, err := OpenProfiles(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slots := recovered.List()
+	if len(slots) != 1 || slots[0].ID != slot.ID || slots[0].Level != 4 {
+		t.Fatalf("unexpected recovered slots: %+v", slots)
+	}
+
+	created, err := recovered.Create("Bob", Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == slot.ID {
+		t.Errorf("recovered slot ID %q was reused", slot.ID)
+	}
+}
@@ -19,76 +19,157 @@
 package save
 
 import (
+	"Unbewohnte/capyclick/bignum"
+	"Unbewohnte/capyclick/logger"
+	"Unbewohnte/capyclick/storage"
 	"encoding/json"
-	"io"
-	"os"
 	"time"
 )
 
-const CurrentVersion uint8 = 1
+const CurrentVersion uint8 = 4
 
 type Save struct {
-	SaveVersion    uint8  `json:"saveVersion"`
-	Points         uint64 `json:"points"`
-	Level          uint32 `json:"level"`
-	CreatedUnix    uint64 `json:"createdUnix"`
-	LastOpenedUnix uint64 `json:"lastOpenedUnix"`
-	TimesClicked   uint64 `json:"timesClicked"`
-	PassiveIncome  uint64 `json:"passiveIncome"`
+	SaveVersion            uint8             `json:"saveVersion"`
+	Points                 bignum.Number     `json:"points"`
+	Level                  uint32            `json:"level"`
+	CreatedUnix            uint64            `json:"createdUnix"`
+	LastOpenedUnix         uint64            `json:"lastOpenedUnix"`
+	LastSavedUnix          uint64            `json:"lastSavedUnix"`
+	TimesClicked           uint64            `json:"timesClicked"`
+	PassiveIncome          bignum.Number     `json:"passiveIncome"`
+	Upgrades               map[string]uint32 `json:"upgrades"`
+	LifetimePoints         bignum.Number     `json:"lifetimePoints"`
+	GoldenMandarins        bignum.Number     `json:"goldenMandarins"`
+	Prestiges              uint32            `json:"prestiges"`
+	MandarinRainsCompleted uint64            `json:"mandarinRainsCompleted"`
+	PlaytimeSeconds        uint64            `json:"playtimeSeconds"`
+	Achievements           map[string]uint64 `json:"achievements"`
+	Modified               bool              `json:"modified"`
 }
 
 // Returns a blank save file structure
 func Default() Save {
 	return Save{
-		SaveVersion:    CurrentVersion,
-		Points:         0,
-		Level:          1,
-		CreatedUnix:    uint64(time.Now().Unix()),
-		LastOpenedUnix: uint64(time.Now().Unix()),
-		TimesClicked:   0,
-		PassiveIncome:  0,
+		SaveVersion:            CurrentVersion,
+		Points:                 bignum.Number{},
+		Level:                  1,
+		CreatedUnix:            uint64(time.Now().Unix()),
+		LastOpenedUnix:         uint64(time.Now().Unix()),
+		LastSavedUnix:          uint64(time.Now().Unix()),
+		TimesClicked:           0,
+		PassiveIncome:          bignum.Number{},
+		Upgrades:               make(map[string]uint32),
+		LifetimePoints:         bignum.Number{},
+		GoldenMandarins:        bignum.Number{},
+		Prestiges:              0,
+		MandarinRainsCompleted: 0,
+		PlaytimeSeconds:        0,
+		Achievements:           make(map[string]uint64),
+		Modified:               false,
 	}
 }
 
-// Tries to retrieve save from given json file
-func FromFile(path string) (*Save, error) {
-	saveFile, err := os.Open(path)
+// Returns a deep copy that can be handed to another goroutine
+func (s Save) Clone() Save {
+	clone := s
+
+	clone.Upgrades = make(map[string]uint32, len(s.Upgrades))
+	for id, owned := range s.Upgrades {
+		clone.Upgrades[id] = owned
+	}
+
+	clone.Achievements = make(map[string]uint64, len(s.Achievements))
+	for id, unlocked := range s.Achievements {
+		clone.Achievements[id] = unlocked
+	}
+
+	return clone
+}
+
+// Adds earned points to both current and lifetime counters
+func (s *Save) Earn(points bignum.Number) {
+	s.Points = s.Points.Add(points)
+	s.LifetimePoints = s.LifetimePoints.Add(points)
+}
+
+// Version saves started being signed at
+const signedVersion uint8 = 4
+
+// Upgrades and unpacks save data of any version, verification is left to the caller
+func decode(data []byte) (Save, envelope, error) {
+	var header struct {
+		SaveVersion uint8 `json:"saveVersion"`
+	}
+	err := json.Unmarshal(data, &header)
 	if err != nil {
-		return nil, err
+		return Save{}, envelope{}, err
 	}
-	defer saveFile.Close()
 
-	saveBytes, err := io.ReadAll(saveFile)
+	migrated, err := migrations.Apply(data)
 	if err != nil {
-		return nil, err
+		return Save{}, envelope{}, err
+	}
+
+	var saveEnvelope envelope
+	err = json.Unmarshal(migrated, &saveEnvelope)
+	if err != nil {
+		return Save{}, envelope{}, err
 	}
 
 	var save Save
-	err = json.Unmarshal(saveBytes, &save)
+	err = json.Unmarshal(saveEnvelope.Data, &save)
 	if err != nil {
-		return nil, err
+		return Save{}, envelope{}, err
 	}
+	save.SaveVersion = CurrentVersion
 
-	return &save, nil
+	// Files without a version are of the very first one
+	if header.SaveVersion < signedVersion {
+		saveEnvelope.legacy = true
+	}
+
+	return save, saveEnvelope, nil
 }
 
-// Creates save file with given fields
-func Create(path string, save Save) error {
-	saveFile, err := os.Create(path)
-	if err != nil {
+// Tries to retrieve save kept under the key, upgrading older versions.
+// Falls back to the backup if the value is damaged. If the save was modified
+// outside the game, it is returned along with a *TamperedError
+func Load(store storage.Store, key string) (*Save, error) {
+	var save Save
+	var saveEnvelope envelope
+	usedBackup, err := storage.LoadWithBackup(store, key, func(data []byte) error {
+		var err error
+		save, saveEnvelope, err = decode(data)
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	defer saveFile.Close()
 
-	saveJsonBytes, err := json.MarshalIndent(save, "", " ")
+	if usedBackup {
+		logger.Warning("[Save] \"%s\" is damaged, restored progress from its backup", key)
+	}
+
+	reason := saveEnvelope.verify()
+	if reason != "" {
+		return &save, &TamperedError{Key: key, Reason: reason}
+	}
+
+	return &save, nil
+}
+
+// Writes a signed save under the key, keeping the previous one as a backup
+func Write(store storage.Store, key string, save Save) error {
+	save.SaveVersion = CurrentVersion
+	saveEnvelope, err := seal(save)
 	if err != nil {
 		return err
 	}
 
-	_, err = saveFile.Write(saveJsonBytes)
+	saveJsonBytes, err := json.MarshalIndent(saveEnvelope, "", " ")
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return store.Save(key, saveJsonBytes)
 }
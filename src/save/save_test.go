@@ -0,0 +1,337 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package save
+
+import (
+	"Unbewohnte/capyclick/bignum"
+	"Unbewohnte/capyclick/migration"
+	"Unbewohnte/capyclick/storage"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func mustParse(t *testing.T, s string) bignum.Number {
+	t.Helper()
+	n, err := bignum.Parse(s)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %s", s, err)
+	}
+	return n
+}
+
+// Fails the test unless err says the save is an unsigned one of an older version
+func expectLegacy(t *testing.T, err error) {
+	t.Helper()
+	var tamperedErr *TamperedError
+	if !errors.As(err, &tamperedErr) || tamperedErr.Reason != "unsigned legacy save" {
+		t.Fatalf("expected an unsigned legacy save error, got %v", err)
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	tests := []struct {
+		file            string
+		points          string
+		lifetimePoints  string
+		goldenMandarins string
+		passiveIncome   string
+		level           uint32
+		upgrades        map[string]uint32
+	}{
+		{
+			file:            "save_v1.json",
+			points:          "1234",
+			lifetimePoints:  "1234",
+			goldenMandarins: "0",
+			passiveIncome:   "6",
+			level:           7,
+		},
+		{
+			file:            "save_v2.json",
+			points:          "18446744073709551615",
+			lifetimePoints:  "50000",
+			goldenMandarins: "2",
+			passiveIncome:   "11",
+			level:           12,
+			upgrades:        map[string]uint32{"grass_patch": 3, "strong_paws": 5},
+		},
+		{
+			file:            "save_v3.json",
+			points:          "123456789012345678901234567890",
+			lifetimePoints:  "999999999999999999999999999999",
+			goldenMandarins: "15",
+			passiveIncome:   "39",
+			level:           40,
+			upgrades:        map[string]uint32{"hot_spring": 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			// Old saves were never signed, the tamper policy decides what to do with them
+			save, err := Load(storage.NewFiles("testdata"), test.file)
+			expectLegacy(t, err)
+
+			if save.SaveVersion != CurrentVersion {
+				t.Errorf("version is %d, expected %d", save.SaveVersion, CurrentVersion)
+			}
+			if save.Points.Cmp(mustParse(t, test.points)) != 0 {
+				t.Errorf("points are %s, expected %s", save.Points, test.points)
+			}
+			if save.LifetimePoints.Cmp(mustParse(t, test.lifetimePoints)) != 0 {
+				t.Errorf("lifetime points are %s, expected %s", save.LifetimePoints, test.lifetimePoints)
+			}
+			if save.GoldenMandarins.Cmp(mustParse(t, test.goldenMandarins)) != 0 {
+				t.Errorf("golden mandarins are %s, expected %s", save.GoldenMandarins, test.goldenMandarins)
+			}
+			if save.PassiveIncome.Cmp(mustParse(t, test.passiveIncome)) != 0 {
+				t.Errorf("passive income is %s, expected %s", save.PassiveIncome, test.passiveIncome)
+			}
+			if save.Level != test.level {
+				t.Errorf("level is %d, expected %d", save.Level, test.level)
+			}
+			if save.Modified {
+				t.Errorf("unsigned save was marked before the policy was applied")
+			}
+			for id, owned := range test.upgrades {
+				if save.Upgrades[id] != owned {
+					t.Errorf("owns %d of %s, expected %d", save.Upgrades[id], id, owned)
+				}
+			}
+		})
+	}
+}
+
+func TestMigratedSaveRoundTrip(t *testing.T) {
+	old, err := Load(storage.NewFiles("testdata"), "save_v2.json")
+	expectLegacy(t, err)
+
+	store := storage.NewMemory()
+	err = Write(store, "save.json", *old)
+	if err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+
+	loaded, err := Load(store, "save.json")
+	if err != nil {
+		t.Fatalf("failed to load again: %s", err)
+	}
+
+	if loaded.Points.Cmp(old.Points) != 0 || loaded.LifetimePoints.Cmp(old.LifetimePoints) != 0 {
+		t.Errorf("points changed after a round trip: %s/%s -> %s/%s",
+			old.Points, old.LifetimePoints, loaded.Points, loaded.LifetimePoints)
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	store := storage.NewMemory()
+
+	// A readable backup must not be used in place of a newer save
+	err := Write(store, "save.json", Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Save("save.json", []byte(`{"saveVersion": 200, "points": "5"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Load(store, "save.json")
+	var newerErr *migration.NewerVersionError
+	if !errors.As(err, &newerErr) {
+		t.Fatalf("expected a newer version error, got %v", err)
+	}
+	if newerErr.Version != 200 || newerErr.Supported != CurrentVersion {
+		t.Errorf("unexpected error contents: %+v", newerErr)
+	}
+}
+
+func TestCreatedSaveVerifies(t *testing.T) {
+	store := storage.NewMemory()
+	original := Default()
+	original.Points = bignum.New(42)
+	err := Write(store, "save.json", original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(store, "save.json")
+	if err != nil {
+		t.Fatalf("untouched save failed to verify: %s", err)
+	}
+	if loaded.Points.Cmp(original.Points) != 0 || loaded.Modified {
+		t.Errorf("unexpected save: %+v", loaded)
+	}
+}
+
+func TestLoadDetectsTampering(t *testing.T) {
+	store := storage.NewMemory()
+	original := Default()
+	original.Points = bignum.New(42)
+	err := Write(store, "save.json", original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := store.Load("save.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = bytes.Replace(data, []byte(`"points": "42"`), []byte(`"points": "999999999"`), 1)
+	err = store.Save("save.json", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(store, "save.json")
+	var tamperedErr *TamperedError
+	if !errors.As(err, &tamperedErr) {
+		t.Fatalf("expected a tampered error, got %v", err)
+	}
+	if loaded == nil || loaded.Points.Cmp(bignum.New(999999999)) != 0 {
+		t.Fatalf("tampered save should still be returned, got %+v", loaded)
+	}
+
+	if !HandleTampered(*loaded, TamperMark).Modified {
+		t.Error("mark policy did not mark the save")
+	}
+	if !HandleTampered(*loaded, TamperReset).Points.IsZero() {
+		t.Error("reset policy did not reset the save")
+	}
+	if HandleTampered(*loaded, TamperWarn).Points.Cmp(loaded.Points) != 0 {
+		t.Error("warn policy changed the save")
+	}
+}
+
+func TestForgedLegacySaveIsNotTrusted(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "legacy flag on a current envelope",
+			data: `{"saveVersion":4,"legacy":true,"data":{"points":"999999999999","level":50}}`,
+		},
+		{
+			name: "current envelope without a signature",
+			data: `{"saveVersion":4,"data":{"points":"999999999999","level":50}}`,
+		},
+		{
+			name: "downgraded to an unsigned version",
+			data: `{"saveVersion":3,"points":"999999999999","level":50}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := storage.NewMemory()
+			err := store.Save("save.json", []byte(test.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := Load(store, "save.json")
+			var tamperedErr *TamperedError
+			if !errors.As(err, &tamperedErr) {
+				t.Fatalf("expected a tampered error, got %v", err)
+			}
+
+			// Every policy applies, forged points do not survive a reset
+			if !HandleTampered(*loaded, TamperReset).Points.IsZero() {
+				t.Errorf("reset policy kept forged points")
+			}
+			if !HandleTampered(*loaded, TamperMark).Modified {
+				t.Errorf("mark policy did not mark the save")
+			}
+			if HandleTampered(*loaded, TamperWarn).Points.Cmp(loaded.Points) != 0 {
+				t.Errorf("warn policy changed the save")
+			}
+		})
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	original := Default()
+	original.Upgrades["strong_paws"] = 1
+	original.Achievements["first_click"] = 100
+
+	clone := original.Clone()
+	original.Upgrades["strong_paws"] = 2
+	original.Achievements["clicks_1000"] = 200
+
+	if clone.Upgrades["strong_paws"] != 1 || len(clone.Achievements) != 1 {
+		t.Errorf("clone changed along with the original: %+v", clone)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	original := Default()
+	original.Points = mustParse(t, "123456789012345678901234567890")
+	original.Level = 33
+	original.Upgrades["hot_spring"] = 4
+
+	exported, err := Export(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := Import("  " + exported + "\n")
+	if err != nil {
+		t.Fatalf("failed to import: %s", err)
+	}
+
+	if imported.Points.Cmp(original.Points) != 0 || imported.Level != 33 || imported.Upgrades["hot_spring"] != 4 {
+		t.Errorf("imported save differs: %+v", imported)
+	}
+}
+
+func TestImportRejectsGarbage(t *testing.T) {
+	for _, exported := range []string{"", "hello", ExportPrefix + "!!!", ExportPrefix + "aGVsbG8="} {
+		_, err := Import(exported)
+		if !errors.Is(err, ErrInvalidExport) {
+			t.Errorf("%q: expected an invalid export error, got %v", exported, err)
+		}
+	}
+}
+
+func TestImportMigratesOldSaves(t *testing.T) {
+	data, err := storage.NewFiles("testdata").Load("save_v1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write(data)
+	writer.Close()
+
+	imported, err := Import(ExportPrefix + base64.StdEncoding.EncodeToString(compressed.Bytes()))
+	expectLegacy(t, err)
+
+	if imported.Level != 7 || imported.LifetimePoints.Cmp(bignum.New(1234)) != 0 {
+		t.Errorf("old save was not migrated: %+v", imported)
+	}
+	if !HandleTampered(*imported, TamperReset).Points.IsZero() {
+		t.Errorf("reset policy kept the unsigned import")
+	}
+}
@@ -0,0 +1,109 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package save
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Every exported save starts with this, so it is easy to tell apart from random text
+const ExportPrefix string = "capyclick:"
+
+// Biggest uncompressed save an import accepts
+const maxImportSize int64 = 1 << 20
+
+var ErrInvalidExport error = errors.New("not an exported capyclick save")
+
+// Packs the save into a compressed base64 string that can be copied between machines
+func Export(save Save) (string, error) {
+	save.SaveVersion = CurrentVersion
+	saveEnvelope, err := seal(save)
+	if err != nil {
+		return "", err
+	}
+
+	envelopeBytes, err := json.Marshal(saveEnvelope)
+	if err != nil {
+		return "", err
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err = writer.Write(envelopeBytes)
+	if err != nil {
+		return "", err
+	}
+	err = writer.Close()
+	if err != nil {
+		return "", err
+	}
+
+	return ExportPrefix + base64.StdEncoding.EncodeToString(compressed.Bytes()), nil
+}
+
+// Unpacks an exported save, upgrading it if it came from an older version.
+// Like Load, a save modified outside the game is returned along with a *TamperedError
+func Import(exported string) (*Save, error) {
+	exported = strings.TrimSpace(exported)
+	if !strings.HasPrefix(exported, ExportPrefix) {
+		return nil, ErrInvalidExport
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(exported, ExportPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidExport, err)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidExport, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxImportSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidExport, err)
+	}
+	if int64(len(data)) > maxImportSize {
+		return nil, fmt.Errorf("%w: too big", ErrInvalidExport)
+	}
+
+	save, saveEnvelope, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if save.Level == 0 {
+		return nil, fmt.Errorf("%w: level 0", ErrInvalidExport)
+	}
+
+	reason := saveEnvelope.verify()
+	if reason != "" {
+		return &save, &TamperedError{Key: "import", Reason: reason}
+	}
+
+	return &save, nil
+}
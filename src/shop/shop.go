@@ -0,0 +1,172 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package shop
+
+import (
+	"Unbewohnte/capyclick/bignum"
+	"Unbewohnte/capyclick/prestige"
+	"Unbewohnte/capyclick/save"
+	"errors"
+)
+
+type Kind uint8
+
+const (
+	// Adds flat points to a single click
+	ClickBonusUpgrade Kind = iota
+	// Multiplies points gained from a single click
+	ClickMultiplierUpgrade
+	// Adds flat points to the passive income every second
+	PassiveIncomeUpgrade
+	// Boosts mandarin rain reward by a percentage
+	RainRewardUpgrade
+)
+
+type Upgrade struct {
+	ID          string
+	Name        string
+	Description string
+	Kind        Kind
+	BaseCost    uint64
+	CostGrowth  float64
+	Effect      float64
+}
+
+// All upgrades available for purchase
+var Catalog = []Upgrade{
+	{
+		ID:          "strong_paws",
+		Name:        "Strong paws",
+		Description: "+1 point per click",
+		Kind:        ClickBonusUpgrade,
+		BaseCost:    15,
+		CostGrowth:  1.15,
+		Effect:      1.0,
+	},
+	{
+		ID:          "golden_paws",
+		Name:        "Golden paws",
+		Description: "+100% points per click",
+		Kind:        ClickMultiplierUpgrade,
+		BaseCost:    2500,
+		CostGrowth:  2.5,
+		Effect:      1.0,
+	},
+	{
+		ID:          "grass_patch",
+		Name:        "Grass patch",
+		Description: "+1 point per second",
+		Kind:        PassiveIncomeUpgrade,
+		BaseCost:    50,
+		CostGrowth:  1.15,
+		Effect:      1.0,
+	},
+	{
+		ID:          "hot_spring",
+		Name:        "Hot spring",
+		Description: "+15 points per second",
+		Kind:        PassiveIncomeUpgrade,
+		BaseCost:    1200,
+		CostGrowth:  1.18,
+		Effect:      15.0,
+	},
+	{
+		ID:          "citrus_grove",
+		Name:        "Citrus grove",
+		Description: "+25% mandarin rain reward",
+		Kind:        RainRewardUpgrade,
+		BaseCost:    500,
+		CostGrowth:  1.5,
+		Effect:      0.25,
+	},
+}
+
+var (
+	ErrUnknownUpgrade  error = errors.New("unknown upgrade")
+	ErrNotEnoughPoints error = errors.New("not enough points")
+)
+
+// Returns an upgrade with given ID
+func ByID(id string) (Upgrade, bool) {
+	for _, upgrade := range Catalog {
+		if upgrade.ID == id {
+			return upgrade, true
+		}
+	}
+
+	return Upgrade{}, false
+}
+
+// Returns how many points the next purchase of the upgrade costs when
+// the player already owns given amount of it
+func (u Upgrade) Cost(owned uint32) bignum.Number {
+	return bignum.New(u.BaseCost).MulFloatPow(u.CostGrowth, uint64(owned))
+}
+
+// Tries to buy an upgrade with given ID, spending save's points
+func Buy(s *save.Save, id string) error {
+	upgrade, ok := ByID(id)
+	if !ok {
+		return ErrUnknownUpgrade
+	}
+
+	if s.Upgrades == nil {
+		s.Upgrades = make(map[string]uint32)
+	}
+
+	cost := upgrade.Cost(s.Upgrades[id])
+	if s.Points.Cmp(cost) < 0 {
+		return ErrNotEnoughPoints
+	}
+
+	s.Points = s.Points.Sub(cost)
+	s.Upgrades[id]++
+
+	return nil
+}
+
+// Sums the effect of all owned upgrades of given kind
+func totalEffect(s save.Save, kind Kind) float64 {
+	var total float64 = 0.0
+	for _, upgrade := range Catalog {
+		if upgrade.Kind != kind {
+			continue
+		}
+		total += upgrade.Effect * float64(s.Upgrades[upgrade.ID])
+	}
+
+	return total
+}
+
+// Returns how many points a single click is worth
+func ClickPoints(s save.Save) bignum.Number {
+	base := bignum.FromFloat(1.0 + totalEffect(s, ClickBonusUpgrade))
+	return base.MulFloat((1.0 + totalEffect(s, ClickMultiplierUpgrade)) * prestige.Multiplier(s))
+}
+
+// Returns how many points are passively gained every second
+func PassiveIncome(s save.Save) bignum.Number {
+	base := s.PassiveIncome.Add(bignum.FromFloat(totalEffect(s, PassiveIncomeUpgrade)))
+	return base.MulFloat(prestige.Multiplier(s))
+}
+
+// Returns mandarin rain reward with rain reward upgrades applied
+func RainReward(s save.Save, base bignum.Number) bignum.Number {
+	return base.MulFloat(1.0 + totalEffect(s, RainRewardUpgrade))
+}
@@ -0,0 +1,69 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package sim
+
+import (
+	"Unbewohnte/capyclick/bignum"
+	"Unbewohnte/capyclick/conf"
+	"Unbewohnte/capyclick/save"
+	"Unbewohnte/capyclick/shop"
+	"time"
+)
+
+type OfflineReport struct {
+	// How long the game was closed according to the clock
+	Away time.Duration
+	// How much of that time was actually rewarded
+	Counted time.Duration
+	// How many points were earned
+	Points bignum.Number
+}
+
+// Calculates how many points were passively earned while the game was closed.
+// Clock going backwards yields nothing, too big time gaps are cut to the configured cap.
+// Saves that never recorded when they were saved get nothing: the time they were opened
+// is not when the game was closed, the whole previous session would count as offline
+func OfflineEarnings(s save.Save, config conf.Configuration, now time.Time) OfflineReport {
+	var report OfflineReport
+
+	lastSeen := s.LastSavedUnix
+	nowUnix := now.Unix()
+	if lastSeen == 0 || nowUnix <= int64(lastSeen) {
+		// Nothing to reward or the clock was turned back
+		return report
+	}
+
+	seconds := uint64(nowUnix) - lastSeen
+	report.Away = time.Duration(seconds) * time.Second
+	if seconds > config.OfflineIncomeCapSeconds {
+		seconds = config.OfflineIncomeCapSeconds
+	}
+	report.Counted = time.Duration(seconds) * time.Second
+
+	efficiency := config.OfflineIncomeEfficiency
+	if efficiency < 0.0 {
+		efficiency = 0.0
+	} else if efficiency > 1.0 {
+		efficiency = 1.0
+	}
+
+	report.Points = shop.PassiveIncome(s).MulFloat(float64(seconds) * efficiency)
+
+	return report
+}
@@ -0,0 +1,79 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package sim
+
+import (
+	"Unbewohnte/capyclick/bignum"
+	"Unbewohnte/capyclick/conf"
+	"Unbewohnte/capyclick/save"
+	"testing"
+	"time"
+)
+
+func TestOfflineEarnings(t *testing.T) {
+	config := conf.Default()
+	config.OfflineIncomeEfficiency = 0.5
+	config.OfflineIncomeCapSeconds = 60 * 60
+
+	tests := []struct {
+		name       string
+		lastSaved  uint64
+		lastOpened uint64
+		away       time.Duration
+		counted    time.Duration
+		points     uint64
+	}{
+		{"a minute away", uint64(testNow.Unix()) - 60, 0, time.Minute, time.Minute, 300},
+		{"over the cap", uint64(testNow.Unix()) - 3*60*60, 0, 3 * time.Hour, time.Hour, 18000},
+		{"older save", 0, uint64(testNow.Unix()) - 10, 0, 0, 0},
+		{"clock turned back", uint64(testNow.Unix()) + 60, 0, 0, 0, 0},
+		{"never seen", 0, 0, 0, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := save.Default()
+			s.PassiveIncome = bignum.New(10)
+			s.LastSavedUnix = test.lastSaved
+			s.LastOpenedUnix = test.lastOpened
+
+			report := OfflineEarnings(s, config, testNow)
+			if report.Away != test.away {
+				t.Errorf("expected to be away for %s, got %s", test.away, report.Away)
+			}
+			if report.Counted != test.counted {
+				t.Errorf("expected %s to be counted, got %s", test.counted, report.Counted)
+			}
+			expectPoints(t, "points", report.Points, test.points)
+		})
+	}
+}
+
+func TestOfflineEfficiencyIsClamped(t *testing.T) {
+	s := save.Default()
+	s.PassiveIncome = bignum.New(1)
+	s.LastSavedUnix = uint64(testNow.Unix()) - 100
+
+	config := conf.Default()
+	config.OfflineIncomeEfficiency = 3.0
+	expectPoints(t, "points", OfflineEarnings(s, config, testNow).Points, 100)
+
+	config.OfflineIncomeEfficiency = -1.0
+	expectPoints(t, "points", OfflineEarnings(s, config, testNow).Points, 0)
+}
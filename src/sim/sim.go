@@ -0,0 +1,192 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package sim
+
+import (
+	"Unbewohnte/capyclick/achievements"
+	"Unbewohnte/capyclick/bignum"
+	"Unbewohnte/capyclick/save"
+	"Unbewohnte/capyclick/shop"
+	"math"
+	"time"
+)
+
+const (
+	// How often passive income is paid
+	IncomePeriod time.Duration = time.Second
+	// Every time the capybara is clicked this many times a mandarin rain starts
+	ClicksPerRain uint64 = 100
+	// Which part of the next level requirement a completed mandarin rain rewards (before upgrades)
+	RainRewardDivisor uint64 = 5
+	// Levels stop here instead of wrapping around
+	MaxLevel uint32 = math.MaxUint32
+)
+
+// Everything the player did during a single step
+type Inputs struct {
+	// How much game time the step covers
+	Delta time.Duration
+	// How many times the capybara was clicked
+	Clicks uint32
+	// The full mandarin box has been brought to the capybara
+	RainDelivered bool
+	// Wall clock time, used to date unlocked achievements
+	Now time.Time
+}
+
+type EventKind uint8
+
+const (
+	// The capybara was clicked and points were earned
+	Clicked EventKind = iota
+	// A second has passed and passive income was earned
+	PassiveIncomeEarned
+	// New levels were reached, one event however many levels were gained
+	LeveledUp
+	// A mandarin rain has begun
+	RainStarted
+	// A mandarin rain was completed and rewarded
+	RainCompleted
+	// An achievement was unlocked
+	AchievementUnlocked
+)
+
+// Something noteworthy that happened during a step
+type Event struct {
+	Kind EventKind
+	// Points earned, if any
+	Points bignum.Number
+	// Highest level reached for LeveledUp
+	Level uint32
+	// Unlocked achievement for AchievementUnlocked
+	Achievement achievements.Achievement
+}
+
+// Game rules and everything they work with, free of any rendering or input devices
+type State struct {
+	Save save.Save
+	// Whether a mandarin rain is currently going on
+	RainInProgress bool
+	// Game time passed since the last passive income
+	incomeElapsed time.Duration
+}
+
+// Returns a state continuing from given save
+func New(s save.Save) State {
+	return State{
+		Save:           s,
+		RainInProgress: false,
+		incomeElapsed:  0,
+	}
+}
+
+// Returns how many points required to be considered of level
+func PointsForLevel(level uint32) bignum.Number {
+	l := bignum.New(uint64(level))
+	return l.Mul(l).MulUint(25)
+}
+
+// Returns the highest level given points are enough for
+func LevelForPoints(points bignum.Number) uint32 {
+	level, ok := points.DivUint(25).Sqrt().Uint64()
+	if !ok || level > uint64(MaxLevel) {
+		return MaxLevel
+	}
+
+	return uint32(level)
+}
+
+// Returns how many points are left until the next level
+func (s *State) PointsToNextLevel() bignum.Number {
+	if s.Save.Level >= MaxLevel {
+		return bignum.Number{}
+	}
+
+	return PointsForLevel(s.Save.Level + 1).Sub(s.Save.Points)
+}
+
+// Returns how many points completing a mandarin rain right now would give
+func (s *State) RainReward() bignum.Number {
+	next := s.Save.Level
+	if next < MaxLevel {
+		next++
+	}
+
+	return shop.RainReward(s.Save, PointsForLevel(next).DivUint(RainRewardDivisor))
+}
+
+// Forgets everything that is not kept in the save, used when the save is replaced as a whole
+func (s *State) Reset() {
+	s.RainInProgress = false
+	s.incomeElapsed = 0
+}
+
+// Advances the game by one step and returns what happened during it
+func (s *State) Step(inputs Inputs) []Event {
+	var events []Event
+
+	// Clicks
+	for i := uint32(0); i < inputs.Clicks; i++ {
+		points := shop.ClickPoints(s.Save)
+		s.Save.TimesClicked++
+		s.Save.Earn(points)
+		events = append(events, Event{Kind: Clicked, Points: points})
+
+		if !s.RainInProgress && s.Save.TimesClicked%ClicksPerRain == 0 {
+			// Have some oranges!
+			s.RainInProgress = true
+			events = append(events, Event{Kind: RainStarted})
+		}
+	}
+
+	// Mandarin rain
+	if s.RainInProgress && inputs.RainDelivered {
+		reward := s.RainReward()
+		s.Save.Earn(reward)
+		s.Save.MandarinRainsCompleted++
+		s.RainInProgress = false
+		events = append(events, Event{Kind: RainCompleted, Points: reward})
+	}
+
+	// Passive income, paid for every whole period the step has covered
+	if inputs.Delta > 0 {
+		s.incomeElapsed += inputs.Delta
+	}
+	for s.incomeElapsed >= IncomePeriod {
+		s.incomeElapsed -= IncomePeriod
+		income := shop.PassiveIncome(s.Save)
+		s.Save.Earn(income)
+		s.Save.PlaytimeSeconds++
+		events = append(events, Event{Kind: PassiveIncomeEarned, Points: income})
+	}
+
+	// Level progression, any number of levels at once. Each level adds a point of passive income
+	if level := LevelForPoints(s.Save.Points); level > s.Save.Level {
+		s.Save.PassiveIncome = s.Save.PassiveIncome.Add(bignum.New(uint64(level - s.Save.Level)))
+		s.Save.Level = level
+		events = append(events, Event{Kind: LeveledUp, Level: level})
+	}
+
+	// Achievements
+	for _, achievement := range achievements.Check(&s.Save, inputs.Now) {
+		events = append(events, Event{Kind: AchievementUnlocked, Achievement: achievement})
+	}
+
+	return events
+}
@@ -0,0 +1,465 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package sim
+
+import (
+	"Unbewohnte/capyclick/bignum"
+	"Unbewohnte/capyclick/save"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var testNow = time.Unix(1700000000, 0)
+
+// Returns events of given kind only
+func only(events []Event, kind EventKind) []Event {
+	var filtered []Event
+	for _, event := range events {
+		if event.Kind == kind {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+func newTestState() State {
+	s := save.Default()
+	s.Level = 1
+	return New(s)
+}
+
+func expectPoints(t *testing.T, name string, got bignum.Number, want uint64) {
+	t.Helper()
+	if got.Cmp(bignum.New(want)) != 0 {
+		t.Errorf("%s: expected %d, got %s", name, want, got)
+	}
+}
+
+func TestPointsForLevel(t *testing.T) {
+	tests := []struct {
+		level  uint32
+		points uint64
+	}{
+		{0, 0},
+		{1, 25},
+		{2, 100},
+		{10, 2500},
+		{100, 250000},
+	}
+
+	for _, test := range tests {
+		expectPoints(t, "points for level", PointsForLevel(test.level), test.points)
+	}
+
+	// Must not overflow on huge levels
+	huge := PointsForLevel(4294967295)
+	if huge.Cmp(bignum.New(18446744073709551615)) <= 0 {
+		t.Errorf("expected points for the last level to exceed uint64, got %s", huge)
+	}
+}
+
+func TestClickEarnsPoints(t *testing.T) {
+	state := newTestState()
+
+	events := state.Step(Inputs{Clicks: 1, Now: testNow})
+	clicks := only(events, Clicked)
+	if len(clicks) != 1 {
+		t.Fatalf("expected 1 click event, got %d", len(clicks))
+	}
+
+	expectPoints(t, "click event", clicks[0].Points, 1)
+	expectPoints(t, "points", state.Save.Points, 1)
+	expectPoints(t, "lifetime points", state.Save.LifetimePoints, 1)
+	if state.Save.TimesClicked != 1 {
+		t.Errorf("expected 1 click to be counted, got %d", state.Save.TimesClicked)
+	}
+
+	state.Step(Inputs{Clicks: 3, Now: testNow})
+	expectPoints(t, "points", state.Save.Points, 4)
+	if state.Save.TimesClicked != 4 {
+		t.Errorf("expected 4 clicks to be counted, got %d", state.Save.TimesClicked)
+	}
+}
+
+func TestClickUpgrades(t *testing.T) {
+	tests := []struct {
+		name            string
+		upgrades        map[string]uint32
+		goldenMandarins uint64
+		points          uint64
+	}{
+		{"no upgrades", nil, 0, 1},
+		{"strong paws", map[string]uint32{"strong_paws": 2}, 0, 3},
+		{"golden paws", map[string]uint32{"golden_paws": 1}, 0, 2},
+		{"both", map[string]uint32{"strong_paws": 3, "golden_paws": 1}, 0, 8},
+		{"golden mandarins", nil, 10, 2},
+		{"everything", map[string]uint32{"strong_paws": 1, "golden_paws": 2}, 10, 12},
+		{"passive upgrades do not count", map[string]uint32{"hot_spring": 5}, 0, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := newTestState()
+			for id, owned := range test.upgrades {
+				state.Save.Upgrades[id] = owned
+			}
+			state.Save.GoldenMandarins = bignum.New(test.goldenMandarins)
+
+			state.Step(Inputs{Clicks: 1, Now: testNow})
+			expectPoints(t, "points", state.Save.Points, test.points)
+		})
+	}
+}
+
+func TestPassiveIncomeOncePerSecond(t *testing.T) {
+	state := newTestState()
+	state.Save.PassiveIncome = bignum.New(5)
+
+	for i := 0; i < 3; i++ {
+		events := state.Step(Inputs{Delta: 250 * time.Millisecond, Now: testNow})
+		if len(only(events, PassiveIncomeEarned)) != 0 {
+			t.Fatalf("income was paid after %d quarters of a second", i+1)
+		}
+	}
+	expectPoints(t, "points before a second passed", state.Save.Points, 0)
+
+	events := state.Step(Inputs{Delta: 250 * time.Millisecond, Now: testNow})
+	income := only(events, PassiveIncomeEarned)
+	if len(income) != 1 {
+		t.Fatalf("expected income to be paid after a second, got %d events", len(income))
+	}
+	expectPoints(t, "income event", income[0].Points, 5)
+	expectPoints(t, "points", state.Save.Points, 5)
+	if state.Save.PlaytimeSeconds != 1 {
+		t.Errorf("expected 1 second of playtime, got %d", state.Save.PlaytimeSeconds)
+	}
+
+	// Another full second
+	for i := 0; i < 4; i++ {
+		state.Step(Inputs{Delta: 250 * time.Millisecond, Now: testNow})
+	}
+	expectPoints(t, "points after 2 seconds", state.Save.Points, 10)
+	if state.Save.PlaytimeSeconds != 2 {
+		t.Errorf("expected 2 seconds of playtime, got %d", state.Save.PlaytimeSeconds)
+	}
+}
+
+func TestPassiveIncomeWithUpgrades(t *testing.T) {
+	state := newTestState()
+	state.Save.PassiveIncome = bignum.New(2)
+	state.Save.Upgrades["grass_patch"] = 3
+	state.Save.Upgrades["hot_spring"] = 1
+	state.Save.GoldenMandarins = bignum.New(10)
+
+	state.Step(Inputs{Delta: time.Second, Now: testNow})
+	// (2 + 3*1 + 1*15) * 2
+	expectPoints(t, "points", state.Save.Points, 40)
+}
+
+func TestPassiveIncomeDoesNotDependOnStepLength(t *testing.T) {
+	for _, step := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 100 * time.Millisecond, time.Second, 5 * time.Second} {
+		state := newTestState()
+		state.Save.PassiveIncome = bignum.New(3)
+
+		var payments int
+		for elapsed := time.Duration(0); elapsed < 10*time.Second; elapsed += step {
+			payments += len(only(state.Step(Inputs{Delta: step, Now: testNow}), PassiveIncomeEarned))
+		}
+
+		if payments != 10 {
+			t.Errorf("%s steps: expected 10 payments, got %d", step, payments)
+		}
+		expectPoints(t, fmt.Sprintf("%s steps", step), state.Save.Points, 30)
+		if state.Save.PlaytimeSeconds != 10 {
+			t.Errorf("%s steps: expected 10 seconds of playtime, got %d", step, state.Save.PlaytimeSeconds)
+		}
+	}
+}
+
+func TestNoIncomeWithoutTime(t *testing.T) {
+	state := newTestState()
+	state.Save.PassiveIncome = bignum.New(1)
+
+	for i := 0; i < 1000; i++ {
+		state.Step(Inputs{Delta: 0, Now: testNow})
+		state.Step(Inputs{Delta: -time.Second, Now: testNow})
+	}
+	expectPoints(t, "points", state.Save.Points, 0)
+}
+
+func TestLevelUp(t *testing.T) {
+	state := newTestState()
+	state.Save.Points = bignum.New(99)
+	state.Save.LifetimePoints = bignum.New(99)
+
+	events := state.Step(Inputs{Now: testNow})
+	if len(only(events, LeveledUp)) != 0 || state.Save.Level != 1 {
+		t.Fatalf("leveled up without enough points")
+	}
+
+	events = state.Step(Inputs{Clicks: 1, Now: testNow})
+	levels := only(events, LeveledUp)
+	if len(levels) != 1 {
+		t.Fatalf("expected 1 level up, got %d", len(levels))
+	}
+	if levels[0].Level != 2 || state.Save.Level != 2 {
+		t.Errorf("expected to reach level 2, got event %d and save %d", levels[0].Level, state.Save.Level)
+	}
+	expectPoints(t, "passive income", state.Save.PassiveIncome, 1)
+
+	// Points are not spent on levels
+	expectPoints(t, "points", state.Save.Points, 100)
+}
+
+func TestSeveralLevelsInOneStep(t *testing.T) {
+	state := newTestState()
+	state.Save.Points = PointsForLevel(5)
+
+	events := state.Step(Inputs{Now: testNow})
+	levels := only(events, LeveledUp)
+	if len(levels) != 1 || levels[0].Level != 5 {
+		t.Fatalf("expected a single level up to 5, got %+v", levels)
+	}
+	if state.Save.Level != 5 {
+		t.Errorf("expected level 5, got %d", state.Save.Level)
+	}
+	expectPoints(t, "passive income", state.Save.PassiveIncome, 4)
+}
+
+func TestHugeLevelJump(t *testing.T) {
+	state := newTestState()
+	// Enough for level 10 million and a bit
+	state.Save.Points = bignum.New(2500000000000000 + 1234)
+
+	events := state.Step(Inputs{Now: testNow})
+	levels := only(events, LeveledUp)
+	if len(levels) != 1 || levels[0].Level != 10000000 {
+		t.Fatalf("expected a single level up to 10000000, got %d events", len(levels))
+	}
+	expectPoints(t, "passive income", state.Save.PassiveIncome, 10000000-1)
+
+	// Just below the requirement of the next level
+	state.Save.Points = PointsForLevel(10000001).Sub(bignum.New(1))
+	if events := state.Step(Inputs{Now: testNow}); len(only(events, LeveledUp)) != 0 {
+		t.Errorf("leveled up short of the requirement")
+	}
+}
+
+func TestLevelStopsAtMax(t *testing.T) {
+	state := newTestState()
+	state.Save.Points = PointsForLevel(MaxLevel).MulUint(1000)
+
+	events := state.Step(Inputs{Now: testNow})
+	levels := only(events, LeveledUp)
+	if len(levels) != 1 || state.Save.Level != MaxLevel {
+		t.Fatalf("expected to stop at the last level, got %d", state.Save.Level)
+	}
+
+	// Nothing wraps around past the last level
+	events = state.Step(Inputs{Clicks: 1, Delta: IncomePeriod, Now: testNow})
+	if len(only(events, LeveledUp)) != 0 || state.Save.Level != MaxLevel {
+		t.Errorf("leveled past the last level to %d", state.Save.Level)
+	}
+	if !state.PointsToNextLevel().IsZero() {
+		t.Errorf("expected nothing left to the next level, got %s", state.PointsToNextLevel())
+	}
+	if state.RainReward().IsZero() {
+		t.Errorf("rain reward wrapped to zero on the last level")
+	}
+}
+
+func TestLevelForPoints(t *testing.T) {
+	tests := []struct {
+		points uint64
+		level  uint32
+	}{
+		{0, 0},
+		{24, 0},
+		{25, 1},
+		{99, 1},
+		{100, 2},
+		{2499, 9},
+		{2500, 10},
+	}
+
+	for _, test := range tests {
+		if level := LevelForPoints(bignum.New(test.points)); level != test.level {
+			t.Errorf("%d points: expected level %d, got %d", test.points, test.level, level)
+		}
+	}
+}
+
+func TestNoLevelUpWithoutPoints(t *testing.T) {
+	state := newTestState()
+	state.Save.Level = 0
+
+	events := state.Step(Inputs{Now: testNow})
+	if len(only(events, LeveledUp)) != 0 {
+		t.Errorf("leveled up with no points at all")
+	}
+}
+
+func TestPointsToNextLevel(t *testing.T) {
+	state := newTestState()
+	state.Save.Points = bignum.New(30)
+	expectPoints(t, "points to level 2", state.PointsToNextLevel(), 70)
+}
+
+func TestRainStartsEveryHundredClicks(t *testing.T) {
+	state := newTestState()
+	state.Save.TimesClicked = ClicksPerRain - 2
+
+	events := state.Step(Inputs{Clicks: 1, Now: testNow})
+	if len(only(events, RainStarted)) != 0 || state.RainInProgress {
+		t.Fatalf("rain started too early")
+	}
+
+	events = state.Step(Inputs{Clicks: 1, Now: testNow})
+	if len(only(events, RainStarted)) != 1 || !state.RainInProgress {
+		t.Fatalf("rain did not start on click number %d", ClicksPerRain)
+	}
+
+	// No new rains while one is going on
+	state.Save.TimesClicked = ClicksPerRain*2 - 1
+	events = state.Step(Inputs{Clicks: 1, Now: testNow})
+	if len(only(events, RainStarted)) != 0 {
+		t.Errorf("another rain started while the first one was in progress")
+	}
+
+	// Nor without clicking
+	state.Reset()
+	events = state.Step(Inputs{Now: testNow})
+	if len(only(events, RainStarted)) != 0 {
+		t.Errorf("rain started without a click")
+	}
+}
+
+func TestRainStartsOncePerBatch(t *testing.T) {
+	state := newTestState()
+
+	events := state.Step(Inputs{Clicks: uint32(ClicksPerRain*2 + 50), Now: testNow})
+	if started := len(only(events, RainStarted)); started != 1 {
+		t.Errorf("expected exactly 1 rain, got %d", started)
+	}
+	if clicks := len(only(events, Clicked)); clicks != int(ClicksPerRain*2+50) {
+		t.Errorf("expected every click to be reported, got %d", clicks)
+	}
+}
+
+func TestRainReward(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    uint32
+		upgrades map[string]uint32
+		reward   uint64
+	}{
+		{"level 1", 1, nil, 20},
+		{"level 10", 10, nil, 605},
+		{"citrus grove", 1, map[string]uint32{"citrus_grove": 2}, 30},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := newTestState()
+			state.Save.Level = test.level
+			for id, owned := range test.upgrades {
+				state.Save.Upgrades[id] = owned
+			}
+			state.RainInProgress = true
+
+			events := state.Step(Inputs{RainDelivered: true, Now: testNow})
+			completed := only(events, RainCompleted)
+			if len(completed) != 1 {
+				t.Fatalf("expected rain to be completed, got %d events", len(completed))
+			}
+
+			expectPoints(t, "reward event", completed[0].Points, test.reward)
+			expectPoints(t, "lifetime points", state.Save.LifetimePoints, test.reward)
+			if state.Save.MandarinRainsCompleted != 1 {
+				t.Errorf("expected 1 completed rain, got %d", state.Save.MandarinRainsCompleted)
+			}
+			if state.RainInProgress {
+				t.Errorf("rain is still in progress after completion")
+			}
+		})
+	}
+}
+
+func TestRainDeliveredWithoutRain(t *testing.T) {
+	state := newTestState()
+
+	events := state.Step(Inputs{RainDelivered: true, Now: testNow})
+	if len(only(events, RainCompleted)) != 0 {
+		t.Errorf("rain was completed without being started")
+	}
+	expectPoints(t, "points", state.Save.Points, 0)
+	if state.Save.MandarinRainsCompleted != 0 {
+		t.Errorf("rain was counted without being started")
+	}
+}
+
+func TestAchievementsUnlockOnce(t *testing.T) {
+	state := newTestState()
+
+	events := state.Step(Inputs{Clicks: 1, Now: testNow})
+	unlocked := only(events, AchievementUnlocked)
+	if len(unlocked) != 1 || unlocked[0].Achievement.ID != "first_click" {
+		t.Fatalf("expected the first click achievement, got %v", unlocked)
+	}
+	if state.Save.Achievements["first_click"] != uint64(testNow.Unix()) {
+		t.Errorf("achievement was not dated with step time")
+	}
+
+	events = state.Step(Inputs{Clicks: 1, Now: testNow.Add(time.Hour)})
+	if len(only(events, AchievementUnlocked)) != 0 {
+		t.Errorf("achievement was unlocked twice")
+	}
+}
+
+func TestAchievementsSeeTheSameStep(t *testing.T) {
+	state := newTestState()
+	state.RainInProgress = true
+
+	events := state.Step(Inputs{RainDelivered: true, Now: testNow})
+	for _, event := range only(events, AchievementUnlocked) {
+		if event.Achievement.ID == "rain_1" {
+			return
+		}
+	}
+	t.Errorf("rain achievement was not unlocked in the step the rain was completed")
+}
+
+func TestReset(t *testing.T) {
+	state := newTestState()
+	state.Save.PassiveIncome = bignum.New(1)
+	state.RainInProgress = true
+	state.Step(Inputs{Delta: 500 * time.Millisecond, Now: testNow})
+
+	state.Reset()
+	if state.RainInProgress {
+		t.Errorf("rain survived the reset")
+	}
+
+	// The income timer starts over as well
+	state.Step(Inputs{Delta: 500 * time.Millisecond, Now: testNow})
+	expectPoints(t, "points", state.Save.Points, 0)
+	state.Step(Inputs{Delta: 500 * time.Millisecond, Now: testNow})
+	expectPoints(t, "points", state.Save.Points, 1)
+}
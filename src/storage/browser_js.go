@@ -0,0 +1,133 @@
+//go:build js
+
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"syscall/js"
+)
+
+// Keeps every key in the browser's local storage under a common prefix
+type Browser struct {
+	Prefix string
+}
+
+func NewBrowser(prefix string) *Browser {
+	return &Browser{
+		Prefix: prefix,
+	}
+}
+
+func localStorage() (js.Value, error) {
+	storage := js.Global().Get("localStorage")
+	if storage.IsUndefined() || storage.IsNull() {
+		return js.Value{}, fmt.Errorf("local storage is not available")
+	}
+
+	return storage, nil
+}
+
+// Calls a local storage method, turning thrown exceptions (such as exceeded quota) into errors
+func call(method string, args ...interface{}) (result js.Value, err error) {
+	storage, err := localStorage()
+	if err != nil {
+		return js.Value{}, err
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("local storage %s failed: %v", method, recovered)
+		}
+	}()
+
+	return storage.Call(method, args...), nil
+}
+
+func (b *Browser) Load(key string) ([]byte, error) {
+	value, err := call("getItem", b.Prefix+key)
+	if err != nil {
+		return nil, err
+	}
+
+	if value.IsNull() {
+		return nil, notExist(b.Prefix + key)
+	}
+
+	return []byte(value.String()), nil
+}
+
+// Each setItem call replaces the value as a whole, so no temporary keys are needed
+func (b *Browser) Save(key string, data []byte) error {
+	previous, err := call("getItem", b.Prefix+key)
+	if err != nil {
+		return err
+	}
+
+	if !previous.IsNull() {
+		_, err = call("setItem", b.Prefix+key+BackupSuffix, previous)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = call("setItem", b.Prefix+key, string(data))
+	return err
+}
+
+// Keys are the ones starting with the prefix, with the prefix cut off
+func (b *Browser) List() ([]string, error) {
+	storage, err := localStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	length := storage.Get("length").Int()
+	for i := 0; i < length; i++ {
+		value, err := call("key", i)
+		if err != nil {
+			return nil, err
+		}
+
+		if value.IsNull() || !strings.HasPrefix(value.String(), b.Prefix) {
+			continue
+		}
+
+		key := strings.TrimPrefix(value.String(), b.Prefix)
+		if isBackup(key) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (b *Browser) Delete(key string) error {
+	_, err := call("removeItem", b.Prefix+key)
+	if err != nil {
+		return err
+	}
+
+	_, err = call("removeItem", b.Prefix+key+BackupSuffix)
+	return err
+}
@@ -0,0 +1,36 @@
+//go:build js
+
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package storage
+
+// Browsers keep everything in local storage, the directory is only used to tell games apart
+func Default(dir string) Store {
+	prefix := "capyclick/"
+	if dir != "" {
+		prefix += dir + "/"
+	}
+
+	return NewBrowser(prefix)
+}
+
+// Browsers keep profiles in local storage as well
+func DefaultProfiles(dir string) Store {
+	return Default(dir)
+}
@@ -1,3 +1,5 @@
+//go:build !js
+
 /*
   	capyclick - Capybara clicker game
     Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
@@ -16,9 +18,14 @@
     along with this program.  If not, see <https://www.gnu.org/licenses/>.
 */
 
-package game
+package storage
+
+// Keeps everything in the directory
+func Default(dir string) Store {
+	return NewFiles(dir)
+}
 
-// Returns how many points required to be considered of level
-func pointsForLevel(level uint32) uint64 {
-	return 25 * uint64(level*level)
+// Keeps every profile in its own directory under dir
+func DefaultProfiles(dir string) Store {
+	return NewProfileDirs(dir)
 }
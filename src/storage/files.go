@@ -0,0 +1,127 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Keeps every key as a file in a directory
+type Files struct {
+	Dir string
+}
+
+func NewFiles(dir string) *Files {
+	return &Files{
+		Dir: dir,
+	}
+}
+
+func (f *Files) path(key string) string {
+	return filepath.Join(f.Dir, key)
+}
+
+func (f *Files) Load(key string) ([]byte, error) {
+	return os.ReadFile(f.path(key))
+}
+
+// Writes data to a temporary file next to the target, flushes it to disk and
+// renames it over the target, so a crash never leaves a half-written file behind
+func (f *Files) Save(key string, data []byte) error {
+	err := os.MkdirAll(f.Dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	path := f.path(key)
+	tempFile, err := os.CreateTemp(f.Dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+
+	_, err = tempFile.Write(data)
+	if err == nil {
+		err = tempFile.Sync()
+	}
+	closeErr := tempFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	err = os.Rename(path, path+BackupSuffix)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		os.Remove(tempPath)
+		return err
+	}
+
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	// Make the renames themselves durable. Not every system can sync a directory
+	dir, err := os.Open(f.Dir)
+	if err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+
+	return nil
+}
+
+// Keys are names of the files, temporary files and backups are skipped
+func (f *Files) List() ([]string, error) {
+	entries, err := os.ReadDir(f.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, entry := range entries {
+		if entry.IsDir() || isBackup(entry.Name()) || strings.Contains(entry.Name(), ".tmp") {
+			continue
+		}
+		keys = append(keys, entry.Name())
+	}
+
+	return keys, nil
+}
+
+func (f *Files) Delete(key string) error {
+	for _, path := range []string{f.path(key), f.path(key) + BackupSuffix} {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
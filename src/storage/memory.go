@@ -0,0 +1,83 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package storage
+
+import (
+	"sync"
+)
+
+// Keeps everything in memory and forgets it when the game is closed. Handy for tests
+type Memory struct {
+	mutex  sync.Mutex
+	values map[string][]byte
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		values: make(map[string][]byte),
+	}
+}
+
+func (m *Memory) Load(key string) ([]byte, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	value, ok := m.values[key]
+	if !ok {
+		return nil, notExist(key)
+	}
+
+	return append([]byte(nil), value...), nil
+}
+
+func (m *Memory) Save(key string, data []byte) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if previous, ok := m.values[key]; ok {
+		m.values[key+BackupSuffix] = previous
+	}
+	m.values[key] = append([]byte(nil), data...)
+
+	return nil
+}
+
+func (m *Memory) List() ([]string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	keys := make([]string, 0, len(m.values))
+	for key := range m.values {
+		if !isBackup(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+func (m *Memory) Delete(key string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.values, key)
+	delete(m.values, key+BackupSuffix)
+
+	return nil
+}
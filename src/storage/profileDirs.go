@@ -0,0 +1,124 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Keeps keys of the form "profile/name" in a directory per profile under the root,
+// so every profile can be copied or removed as a whole. Keys without a profile
+// are kept in the root itself
+type ProfileDirs struct {
+	Root string
+}
+
+func NewProfileDirs(root string) *ProfileDirs {
+	return &ProfileDirs{
+		Root: root,
+	}
+}
+
+// Returns files of the directory the key belongs to along with the key's name in it
+func (p *ProfileDirs) split(key string) (*Files, string, error) {
+	profile, name, found := strings.Cut(key, "/")
+	if !found {
+		return NewFiles(p.Root), key, nil
+	}
+
+	if profile == "" || profile == "." || profile == ".." || name == "" || strings.Contains(name, "/") {
+		return nil, "", errors.New("invalid key \"" + key + "\"")
+	}
+
+	return NewFiles(filepath.Join(p.Root, profile)), name, nil
+}
+
+func (p *ProfileDirs) Load(key string) ([]byte, error) {
+	files, name, err := p.split(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return files.Load(name)
+}
+
+func (p *ProfileDirs) Save(key string, data []byte) error {
+	files, name, err := p.split(key)
+	if err != nil {
+		return err
+	}
+
+	return files.Save(name, data)
+}
+
+func (p *ProfileDirs) List() ([]string, error) {
+	root := NewFiles(p.Root)
+	keys, err := root.List()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(p.Root)
+	if errors.Is(err, os.ErrNotExist) {
+		return keys, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		names, err := NewFiles(filepath.Join(p.Root, entry.Name())).List()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			keys = append(keys, entry.Name()+"/"+name)
+		}
+	}
+
+	return keys, nil
+}
+
+// Removes the key and the profile's directory once it has nothing else in it
+func (p *ProfileDirs) Delete(key string) error {
+	files, name, err := p.split(key)
+	if err != nil {
+		return err
+	}
+
+	err = files.Delete(name)
+	if err != nil {
+		return err
+	}
+
+	if files.Dir != p.Root {
+		// Fails harmlessly if there is something left
+		os.Remove(files.Dir)
+	}
+
+	return nil
+}
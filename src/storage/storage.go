@@ -0,0 +1,87 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package storage
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// Suffix of the key the previous version of a value is kept under
+const BackupSuffix string = ".bak"
+
+// Parse errors wrapping this are returned by LoadWithBackup right away,
+// the backup is not tried for them
+var ErrNoFallback error = errors.New("value must not be replaced with its backup")
+
+// A place where saves and configuration are kept by key
+type Store interface {
+	// Returns the value of the key. Missing keys give an error wrapping os.ErrNotExist
+	Load(key string) ([]byte, error)
+	// Replaces the value of the key as a whole, keeping the previous one under key + BackupSuffix
+	Save(key string, data []byte) error
+	// Returns all keys in no particular order, backups excluded
+	List() ([]string, error)
+	// Removes the key along with its backup
+	Delete(key string) error
+}
+
+// Loads the key and hands its value to parse. If the value can't be loaded
+// or parsed, its backup is tried instead.
+// Returns true if the backup was used and the error of the primary value if both fail
+func LoadWithBackup(store Store, key string, parse func(data []byte) error) (bool, error) {
+	data, err := store.Load(key)
+	if err == nil {
+		err = parse(data)
+		if err == nil {
+			return false, nil
+		}
+
+		if errors.Is(err, ErrNoFallback) {
+			return false, err
+		}
+	}
+
+	backupData, backupErr := store.Load(key + BackupSuffix)
+	if backupErr != nil {
+		return false, err
+	}
+
+	if parse(backupData) != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Returns true if the error means that the key is not in the store
+func IsNotExist(err error) bool {
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// Returns true if the key holds a backup of another key
+func isBackup(key string) bool {
+	return strings.HasSuffix(key, BackupSuffix)
+}
+
+// Returns an error for a key that is not in the store
+func notExist(key string) error {
+	return &os.PathError{Op: "load", Path: key, Err: os.ErrNotExist}
+}
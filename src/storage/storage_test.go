@@ -0,0 +1,133 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package storage
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+var errDamaged = errors.New("damaged")
+
+// Checks the behaviour every store must have
+func testStore(t *testing.T, store Store, keys []string) {
+	_, err := store.Load(keys[0])
+	if !IsNotExist(err) {
+		t.Fatalf("missing key gave %v instead of a not exist error", err)
+	}
+
+	for _, key := range keys {
+		err = store.Save(key, []byte("first "+key))
+		if err != nil {
+			t.Fatalf("failed to save %q: %s", key, err)
+		}
+	}
+
+	err = store.Save(keys[0], []byte("second"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := store.Load(keys[0])
+	if err != nil || string(data) != "second" {
+		t.Fatalf("loaded %q, %v instead of the latest value", data, err)
+	}
+
+	backup, err := store.Load(keys[0] + BackupSuffix)
+	if err != nil || string(backup) != "first "+keys[0] {
+		t.Fatalf("loaded backup %q, %v instead of the previous value", backup, err)
+	}
+
+	listed, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(listed)
+	expected := append([]string(nil), keys...)
+	sort.Strings(expected)
+	if len(listed) != len(expected) {
+		t.Fatalf("listed %v, expected %v", listed, expected)
+	}
+	for index := range listed {
+		if listed[index] != expected[index] {
+			t.Fatalf("listed %v, expected %v", listed, expected)
+		}
+	}
+
+	err = store.Delete(keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = store.Load(keys[0])
+	if !IsNotExist(err) {
+		t.Errorf("deleted key gave %v instead of a not exist error", err)
+	}
+	_, err = store.Load(keys[0] + BackupSuffix)
+	if !IsNotExist(err) {
+		t.Errorf("backup of a deleted key gave %v instead of a not exist error", err)
+	}
+
+	err = store.Delete("never saved")
+	if err != nil {
+		t.Errorf("deleting a missing key failed: %s", err)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	testStore(t, NewMemory(), []string{"config.json", "save.json"})
+}
+
+func TestFiles(t *testing.T) {
+	testStore(t, NewFiles(t.TempDir()), []string{"config.json", "save.json"})
+}
+
+func TestProfileDirs(t *testing.T) {
+	testStore(t, NewProfileDirs(t.TempDir()), []string{"slot1/save.json", "profiles.json", "slot2/save.json"})
+}
+
+func TestLoadWithBackup(t *testing.T) {
+	store := NewMemory()
+	store.Save("key", []byte("good"))
+	store.Save("key", []byte("bad"))
+
+	var parsed string
+	usedBackup, err := LoadWithBackup(store, "key", func(data []byte) error {
+		if string(data) == "bad" {
+			return ErrNoFallback
+		}
+		parsed = string(data)
+		return nil
+	})
+	if err != ErrNoFallback || usedBackup {
+		t.Fatalf("backup must not be used for ErrNoFallback, got %v, %v", usedBackup, err)
+	}
+
+	usedBackup, err = LoadWithBackup(store, "key", func(data []byte) error {
+		if string(data) == "bad" {
+			return errDamaged
+		}
+		parsed = string(data)
+		return nil
+	})
+	if err != nil || !usedBackup || parsed != "good" {
+		t.Fatalf("backup was not used: %v, %v, %q", usedBackup, err, parsed)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package timestep
+
+import "time"
+
+// Turns uneven frame times into a whole number of equally long steps,
+// so that whatever is stepped behaves the same at any frame rate
+type Accumulator struct {
+	// How long a single step is
+	Step time.Duration
+	// The most steps a single frame may run. Time beyond that is dropped
+	// so that a long hiccup does not make the game spend frames catching up
+	MaxSteps    int
+	accumulated time.Duration
+}
+
+// Returns an accumulator with given step length and frame step limit
+func New(step time.Duration, maxSteps int) Accumulator {
+	return Accumulator{
+		Step:        step,
+		MaxSteps:    maxSteps,
+		accumulated: 0,
+	}
+}
+
+// Adds the time that passed since the last frame and returns how many steps should be run now
+func (a *Accumulator) Advance(elapsed time.Duration) int {
+	if a.Step <= 0 || elapsed <= 0 {
+		return 0
+	}
+
+	a.accumulated += elapsed
+	steps := int(a.accumulated / a.Step)
+	if a.MaxSteps > 0 && steps > a.MaxSteps {
+		steps = a.MaxSteps
+		a.accumulated = 0
+		return steps
+	}
+
+	a.accumulated -= time.Duration(steps) * a.Step
+	return steps
+}
+
+// Returns how far the leftover time is into the next step in [0.0; 1.0)
+func (a *Accumulator) Alpha() float64 {
+	if a.Step <= 0 {
+		return 0.0
+	}
+
+	return float64(a.accumulated) / float64(a.Step)
+}
+
+// Forgets the leftover time
+func (a *Accumulator) Reset() {
+	a.accumulated = 0
+}
@@ -0,0 +1,94 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package timestep
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdvance(t *testing.T) {
+	accumulator := New(10*time.Millisecond, 0)
+
+	tests := []struct {
+		elapsed time.Duration
+		steps   int
+	}{
+		{5 * time.Millisecond, 0},
+		{5 * time.Millisecond, 1},
+		{25 * time.Millisecond, 2},
+		{5 * time.Millisecond, 1},
+		{0, 0},
+		{-time.Second, 0},
+	}
+
+	for index, test := range tests {
+		steps := accumulator.Advance(test.elapsed)
+		if steps != test.steps {
+			t.Errorf("frame %d: expected %d steps, got %d", index, test.steps, steps)
+		}
+	}
+}
+
+func TestStepsDoNotDependOnFrameRate(t *testing.T) {
+	for _, framesPerSecond := range []int{24, 30, 60, 144, 240} {
+		accumulator := New(time.Second/100, 0)
+		frame := time.Second / time.Duration(framesPerSecond)
+
+		total := 0
+		for i := 0; i < framesPerSecond*10; i++ {
+			total += accumulator.Advance(frame)
+		}
+
+		// A rounded frame length may lose a step at most
+		if total < 999 || total > 1000 {
+			t.Errorf("%d FPS: expected 1000 steps in 10 seconds, got %d", framesPerSecond, total)
+		}
+	}
+}
+
+func TestMaxSteps(t *testing.T) {
+	accumulator := New(10*time.Millisecond, 5)
+
+	if steps := accumulator.Advance(time.Second); steps != 5 {
+		t.Fatalf("expected steps to be capped at 5, got %d", steps)
+	}
+
+	// The rest of the hiccup is dropped
+	if steps := accumulator.Advance(10 * time.Millisecond); steps != 1 {
+		t.Errorf("expected the dropped time to be forgotten, got %d steps", steps)
+	}
+}
+
+func TestAlphaAndReset(t *testing.T) {
+	accumulator := New(10*time.Millisecond, 0)
+	accumulator.Advance(25 * time.Millisecond)
+
+	if alpha := accumulator.Alpha(); alpha < 0.49 || alpha > 0.51 {
+		t.Errorf("expected alpha of 0.5, got %f", alpha)
+	}
+
+	accumulator.Reset()
+	if alpha := accumulator.Alpha(); alpha != 0.0 {
+		t.Errorf("expected alpha of 0 after reset, got %f", alpha)
+	}
+	if steps := accumulator.Advance(5 * time.Millisecond); steps != 0 {
+		t.Errorf("leftover time survived the reset")
+	}
+}
@@ -0,0 +1,181 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package tween
+
+import "time"
+
+type sequence struct {
+	steps   []Animation
+	current int
+}
+
+// Returns an animation playing given ones one after another
+func Sequence(steps ...Animation) Animation {
+	return &sequence{steps: steps}
+}
+
+func (s *sequence) Update(delta time.Duration) time.Duration {
+	for s.current < len(s.steps) {
+		delta = s.steps[s.current].Update(delta)
+		if !s.steps[s.current].Done() {
+			return 0
+		}
+		s.current++
+	}
+
+	return delta
+}
+
+func (s *sequence) Done() bool {
+	return s.current >= len(s.steps)
+}
+
+func (s *sequence) Reset() {
+	s.current = 0
+	for _, step := range s.steps {
+		step.Reset()
+	}
+}
+
+type parallel struct {
+	animations []Animation
+}
+
+// Returns an animation playing given ones at the same time, it is done when all of them are
+func Parallel(animations ...Animation) Animation {
+	return &parallel{animations: animations}
+}
+
+func (p *parallel) Update(delta time.Duration) time.Duration {
+	left := delta
+	for _, animation := range p.animations {
+		if animation.Done() {
+			continue
+		}
+
+		animationLeft := animation.Update(delta)
+		if animationLeft < left {
+			left = animationLeft
+		}
+	}
+
+	if !p.Done() {
+		return 0
+	}
+	return left
+}
+
+func (p *parallel) Done() bool {
+	for _, animation := range p.animations {
+		if !animation.Done() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *parallel) Reset() {
+	for _, animation := range p.animations {
+		animation.Reset()
+	}
+}
+
+type repeat struct {
+	animation Animation
+	times     int
+	played    int
+}
+
+// Returns an animation playing the given one given number of times, forever if times is not positive
+func Repeat(animation Animation, times int) Animation {
+	return &repeat{animation: animation, times: times}
+}
+
+func (r *repeat) Update(delta time.Duration) time.Duration {
+	for !r.Done() {
+		left := r.animation.Update(delta)
+		if !r.animation.Done() {
+			return 0
+		}
+
+		r.played++
+		if r.Done() {
+			return left
+		}
+		r.animation.Reset()
+
+		if left == delta {
+			// Took no time at all, would spin forever
+			return 0
+		}
+		delta = left
+	}
+
+	return delta
+}
+
+func (r *repeat) Done() bool {
+	return r.times > 0 && r.played >= r.times
+}
+
+func (r *repeat) Reset() {
+	r.played = 0
+	r.animation.Reset()
+}
+
+// Animations playing on their own, finished ones are dropped
+type Group struct {
+	running []Animation
+}
+
+// Starts playing the animation with the group and returns it
+func (g *Group) Add(animation Animation) Animation {
+	g.running = append(g.running, animation)
+	return animation
+}
+
+// Moves all animations on by delta
+func (g *Group) Update(delta time.Duration) {
+	// Animations may add others while updating
+	for index := 0; index < len(g.running); index++ {
+		g.running[index].Update(delta)
+	}
+
+	running := g.running[:0]
+	for _, animation := range g.running {
+		if !animation.Done() {
+			running = append(running, animation)
+		}
+	}
+	for index := len(running); index < len(g.running); index++ {
+		g.running[index] = nil
+	}
+	g.running = running
+}
+
+// Returns how many animations are playing
+func (g *Group) Len() int {
+	return len(g.running)
+}
+
+// Stops all animations where they are
+func (g *Group) Clear() {
+	g.running = nil
+}
@@ -0,0 +1,134 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package tween
+
+import "math"
+
+// Maps linear progress in [0.0; 1.0] to eased progress. Eased progress starts at 0.0
+// and ends at 1.0, but may go beyond in between
+type Easing func(t float64) float64
+
+const (
+	backOvershoot float64 = 1.70158
+	bounceFactor  float64 = 7.5625
+	bounceWidth   float64 = 2.75
+)
+
+func Linear(t float64) float64 {
+	return t
+}
+
+func InQuad(t float64) float64 {
+	return t * t
+}
+
+func OutQuad(t float64) float64 {
+	return 1.0 - (1.0-t)*(1.0-t)
+}
+
+func InOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2.0 * t * t
+	}
+	return 1.0 - math.Pow(-2.0*t+2.0, 2.0)/2.0
+}
+
+func InCubic(t float64) float64 {
+	return t * t * t
+}
+
+func OutCubic(t float64) float64 {
+	return 1.0 - math.Pow(1.0-t, 3.0)
+}
+
+func InOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4.0 * t * t * t
+	}
+	return 1.0 - math.Pow(-2.0*t+2.0, 3.0)/2.0
+}
+
+func InElastic(t float64) float64 {
+	if t <= 0.0 || t >= 1.0 {
+		return math.Max(0.0, math.Min(1.0, t))
+	}
+	return -math.Pow(2.0, 10.0*t-10.0) * math.Sin((10.0*t-10.75)*(2.0*math.Pi/3.0))
+}
+
+func OutElastic(t float64) float64 {
+	if t <= 0.0 || t >= 1.0 {
+		return math.Max(0.0, math.Min(1.0, t))
+	}
+	return math.Pow(2.0, -10.0*t)*math.Sin((10.0*t-0.75)*(2.0*math.Pi/3.0)) + 1.0
+}
+
+func InOutElastic(t float64) float64 {
+	if t <= 0.0 || t >= 1.0 {
+		return math.Max(0.0, math.Min(1.0, t))
+	}
+
+	wave := math.Sin((20.0*t - 11.125) * (2.0 * math.Pi / 4.5))
+	if t < 0.5 {
+		return -(math.Pow(2.0, 20.0*t-10.0) * wave) / 2.0
+	}
+	return math.Pow(2.0, -20.0*t+10.0)*wave/2.0 + 1.0
+}
+
+func OutBounce(t float64) float64 {
+	switch {
+	case t < 1.0/bounceWidth:
+		return bounceFactor * t * t
+	case t < 2.0/bounceWidth:
+		t -= 1.5 / bounceWidth
+		return bounceFactor*t*t + 0.75
+	case t < 2.5/bounceWidth:
+		t -= 2.25 / bounceWidth
+		return bounceFactor*t*t + 0.9375
+	default:
+		t -= 2.625 / bounceWidth
+		return bounceFactor*t*t + 0.984375
+	}
+}
+
+func InBounce(t float64) float64 {
+	return 1.0 - OutBounce(1.0-t)
+}
+
+func InOutBounce(t float64) float64 {
+	if t < 0.5 {
+		return (1.0 - OutBounce(1.0-2.0*t)) / 2.0
+	}
+	return (1.0 + OutBounce(2.0*t-1.0)) / 2.0
+}
+
+func InBack(t float64) float64 {
+	return (backOvershoot+1.0)*t*t*t - backOvershoot*t*t
+}
+
+func OutBack(t float64) float64 {
+	return 1.0 + (backOvershoot+1.0)*math.Pow(t-1.0, 3.0) + backOvershoot*math.Pow(t-1.0, 2.0)
+}
+
+func InOutBack(t float64) float64 {
+	overshoot := backOvershoot * 1.525
+	if t < 0.5 {
+		return math.Pow(2.0*t, 2.0) * ((overshoot+1.0)*2.0*t - overshoot) / 2.0
+	}
+	return (math.Pow(2.0*t-2.0, 2.0)*((overshoot+1.0)*(t*2.0-2.0)+overshoot) + 2.0) / 2.0
+}
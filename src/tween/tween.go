@@ -0,0 +1,207 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package tween
+
+import "time"
+
+// Anything that plays out over time
+type Animation interface {
+	// Moves the animation on by delta. Returns the part of delta left over after it has finished
+	Update(delta time.Duration) time.Duration
+	Done() bool
+	// Starts the animation over
+	Reset()
+}
+
+// Eases a value from one number to another
+type Tween struct {
+	From     float64
+	To       float64
+	Duration time.Duration
+	Ease     Easing
+	// Receives the value on every update if set
+	Target *float64
+	// Called once the tween has finished
+	OnDone func()
+	// Start from whatever the target holds when the tween starts
+	fromTarget bool
+	started    bool
+	elapsed    time.Duration
+	value      float64
+}
+
+// Returns a tween between two numbers, its value is read with Value
+func New(from float64, to float64, duration time.Duration, ease Easing) *Tween {
+	return &Tween{
+		From:     from,
+		To:       to,
+		Duration: duration,
+		Ease:     ease,
+		value:    from,
+	}
+}
+
+// Returns a tween that moves the target from its value at the start to the given one
+func To(target *float64, to float64, duration time.Duration, ease Easing) *Tween {
+	tween := New(*target, to, duration, ease)
+	tween.Target = target
+	tween.fromTarget = true
+	return tween
+}
+
+// Sets the callback called once the tween has finished and returns the tween
+func (t *Tween) Then(callback func()) *Tween {
+	t.OnDone = callback
+	return t
+}
+
+// Returns the current eased value
+func (t *Tween) Value() float64 {
+	return t.value
+}
+
+// Returns how much of the duration has passed in [0.0; 1.0]
+func (t *Tween) Progress() float64 {
+	if t.Duration <= 0 || t.elapsed >= t.Duration {
+		return 1.0
+	}
+
+	return float64(t.elapsed) / float64(t.Duration)
+}
+
+func (t *Tween) Done() bool {
+	return t.started && t.elapsed >= t.Duration
+}
+
+func (t *Tween) Reset() {
+	t.started = false
+	t.elapsed = 0
+	t.value = t.From
+}
+
+func (t *Tween) Update(delta time.Duration) time.Duration {
+	if t.Done() {
+		return delta
+	}
+
+	if !t.started {
+		t.started = true
+		if t.fromTarget && t.Target != nil {
+			t.From = *t.Target
+		}
+	}
+
+	if delta < 0 {
+		delta = 0
+	}
+
+	t.elapsed += delta
+	left := time.Duration(0)
+	if t.elapsed >= t.Duration {
+		left = t.elapsed - t.Duration
+		t.elapsed = t.Duration
+	}
+
+	ease := t.Ease
+	if ease == nil {
+		ease = Linear
+	}
+	progress := t.Progress()
+	if progress >= 1.0 {
+		// Land exactly on the end value whatever the easing does
+		t.value = t.To
+	} else {
+		t.value = t.From + (t.To-t.From)*ease(progress)
+	}
+
+	if t.Target != nil {
+		*t.Target = t.value
+	}
+
+	if t.Done() && t.OnDone != nil {
+		t.OnDone()
+	}
+
+	return left
+}
+
+type delay struct {
+	duration time.Duration
+	elapsed  time.Duration
+	started  bool
+}
+
+// Returns an animation that just waits
+func Delay(duration time.Duration) Animation {
+	return &delay{duration: duration}
+}
+
+func (d *delay) Update(delta time.Duration) time.Duration {
+	d.started = true
+	if delta < 0 {
+		delta = 0
+	}
+
+	d.elapsed += delta
+	if d.elapsed < d.duration {
+		return 0
+	}
+
+	left := d.elapsed - d.duration
+	d.elapsed = d.duration
+	return left
+}
+
+func (d *delay) Done() bool {
+	return d.started && d.elapsed >= d.duration
+}
+
+func (d *delay) Reset() {
+	d.elapsed = 0
+	d.started = false
+}
+
+type call struct {
+	callback func()
+	called   bool
+}
+
+// Returns an animation that calls the callback and finishes right away
+func Call(callback func()) Animation {
+	return &call{callback: callback}
+}
+
+func (c *call) Update(delta time.Duration) time.Duration {
+	if !c.called {
+		c.called = true
+		if c.callback != nil {
+			c.callback()
+		}
+	}
+
+	return delta
+}
+
+func (c *call) Done() bool {
+	return c.called
+}
+
+func (c *call) Reset() {
+	c.called = false
+}
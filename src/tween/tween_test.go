@@ -0,0 +1,236 @@
+/*
+  	capyclick - Capybara clicker game
+    Copyright (C) 2024  Kasianov Nikolai Alekseevich (Unbewohnte)
+
+    This program is free software: you can redistribute it and/or modify
+    it under the terms of the GNU Affero General Public License as published by
+    the Free Software Foundation, either version 3 of the License, or
+    (at your option) any later version.
+
+    This program is distributed in the hope that it will be useful,
+    but WITHOUT ANY WARRANTY; without even the implied warranty of
+    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+    GNU Affero General Public License for more details.
+
+    You should have received a copy of the GNU Affero General Public License
+    along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package tween
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var easings = map[string]Easing{
+	"Linear":       Linear,
+	"InQuad":       InQuad,
+	"OutQuad":      OutQuad,
+	"InOutQuad":    InOutQuad,
+	"InCubic":      InCubic,
+	"OutCubic":     OutCubic,
+	"InOutCubic":   InOutCubic,
+	"InElastic":    InElastic,
+	"OutElastic":   OutElastic,
+	"InOutElastic": InOutElastic,
+	"InBounce":     InBounce,
+	"OutBounce":    OutBounce,
+	"InOutBounce":  InOutBounce,
+	"InBack":       InBack,
+	"OutBack":      OutBack,
+	"InOutBack":    InOutBack,
+}
+
+func near(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEasingEnds(t *testing.T) {
+	for name, ease := range easings {
+		if !near(ease(0.0), 0.0) {
+			t.Errorf("%s(0) = %v, want 0", name, ease(0.0))
+		}
+		if !near(ease(1.0), 1.0) {
+			t.Errorf("%s(1) = %v, want 1", name, ease(1.0))
+		}
+	}
+}
+
+func TestEasingShapes(t *testing.T) {
+	if !near(InOutQuad(0.5), 0.5) || !near(InOutCubic(0.5), 0.5) || !near(InOutBack(0.5), 0.5) {
+		t.Errorf("in-out easings do not cross the middle")
+	}
+
+	for step := 1; step < 100; step++ {
+		x := float64(step) / 100.0
+		if InQuad(x) > x || OutQuad(x) < x || InCubic(x) > InQuad(x) {
+			t.Fatalf("polynomial easings are off at %v", x)
+		}
+		if OutBounce(x) < 0.0 || OutBounce(x) > 1.0 {
+			t.Fatalf("OutBounce(%v) = %v leaves [0; 1]", x, OutBounce(x))
+		}
+	}
+
+	if InBack(0.2) >= 0.0 {
+		t.Errorf("InBack does not pull back first")
+	}
+	if OutBack(0.8) <= 1.0 {
+		t.Errorf("OutBack does not overshoot")
+	}
+	if OutElastic(0.15) <= 1.0 {
+		t.Errorf("OutElastic does not overshoot")
+	}
+}
+
+func TestTween(t *testing.T) {
+	tween := New(10.0, 20.0, time.Second, Linear)
+	done := 0
+	tween.Then(func() { done++ })
+
+	if left := tween.Update(time.Second / 4); left != 0 || !near(tween.Value(), 12.5) {
+		t.Fatalf("got %v with %v left, want 12.5", tween.Value(), left)
+	}
+	if tween.Done() {
+		t.Fatalf("tween done too early")
+	}
+
+	if left := tween.Update(time.Second); left != time.Second/4 {
+		t.Errorf("left %v, want %v", left, time.Second/4)
+	}
+	if !tween.Done() || tween.Value() != 20.0 || done != 1 {
+		t.Errorf("tween did not finish: value %v, done %v, callbacks %d", tween.Value(), tween.Done(), done)
+	}
+
+	tween.Update(time.Second)
+	if done != 1 {
+		t.Errorf("callback called %d times", done)
+	}
+
+	tween.Reset()
+	if tween.Done() || tween.Value() != 10.0 {
+		t.Errorf("reset did not rewind the tween")
+	}
+}
+
+func TestTweenTarget(t *testing.T) {
+	value := 5.0
+	tween := To(&value, 1.0, time.Second, Linear)
+
+	// Started from whatever the target held at the first update
+	value = 3.0
+	tween.Update(time.Second / 2)
+	if !near(value, 2.0) {
+		t.Errorf("target is %v, want 2", value)
+	}
+
+	tween.Update(time.Second)
+	if value != 1.0 {
+		t.Errorf("target is %v, want 1", value)
+	}
+}
+
+func TestZeroDuration(t *testing.T) {
+	value := 0.0
+	tween := To(&value, 1.0, 0, OutBounce)
+	if left := tween.Update(time.Millisecond); left != time.Millisecond || value != 1.0 || !tween.Done() {
+		t.Errorf("zero tween: value %v, left %v", value, left)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	value := 0.0
+	var calls []string
+	sequence := Sequence(
+		To(&value, 1.0, time.Second, Linear),
+		Call(func() { calls = append(calls, "up") }),
+		Delay(time.Second),
+		To(&value, 0.0, time.Second, Linear),
+		Call(func() { calls = append(calls, "down") }),
+	)
+
+	// Leftover time of one step goes to the next
+	sequence.Update(time.Second + time.Second/2)
+	if value != 1.0 || len(calls) != 1 {
+		t.Fatalf("value %v, calls %v", value, calls)
+	}
+
+	sequence.Update(time.Second)
+	if !near(value, 0.5) {
+		t.Fatalf("value %v, want 0.5", value)
+	}
+
+	if left := sequence.Update(time.Second); left != time.Second/2 || !sequence.Done() {
+		t.Fatalf("sequence not done, left %v", left)
+	}
+	if value != 0.0 || len(calls) != 2 || calls[1] != "down" {
+		t.Errorf("value %v, calls %v", value, calls)
+	}
+
+	sequence.Reset()
+	value = 0.0
+	sequence.Update(time.Second / 2)
+	if !near(value, 0.5) || sequence.Done() {
+		t.Errorf("reset sequence did not start over, value %v", value)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	a, b := 0.0, 0.0
+	parallel := Parallel(
+		To(&a, 1.0, time.Second, Linear),
+		To(&b, 1.0, 2*time.Second, Linear),
+	)
+
+	if left := parallel.Update(time.Second); left != 0 || parallel.Done() {
+		t.Fatalf("parallel done too early")
+	}
+	if a != 1.0 || !near(b, 0.5) {
+		t.Fatalf("a = %v, b = %v", a, b)
+	}
+
+	if left := parallel.Update(2 * time.Second); left != time.Second || !parallel.Done() {
+		t.Errorf("parallel not done, left %v", left)
+	}
+	if b != 1.0 {
+		t.Errorf("b = %v, want 1", b)
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	count := 0
+	repeat := Repeat(Sequence(Delay(time.Second), Call(func() { count++ })), 3)
+
+	repeat.Update(2*time.Second + time.Second/2)
+	if count != 2 || repeat.Done() {
+		t.Fatalf("count %d after 2.5 seconds", count)
+	}
+
+	if left := repeat.Update(time.Second); left != time.Second/2 || count != 3 || !repeat.Done() {
+		t.Errorf("count %d, left %v", count, left)
+	}
+
+	forever := Repeat(Call(func() { count++ }), 0)
+	forever.Update(time.Second)
+	if forever.Done() {
+		t.Errorf("endless repeat finished")
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var group Group
+	value := 0.0
+	group.Add(To(&value, 1.0, time.Second, Linear))
+	group.Add(Delay(3 * time.Second))
+
+	group.Update(time.Second)
+	if group.Len() != 1 || value != 1.0 {
+		t.Fatalf("group holds %d animations, value %v", group.Len(), value)
+	}
+
+	group.Update(2 * time.Second)
+	if group.Len() != 0 {
+		t.Errorf("group holds %d finished animations", group.Len())
+	}
+}